@@ -20,3 +20,9 @@ resource "arvan_floating_ip" "terraform_floating_ip" {
   region      = var.region
   description = "Terraform-created floating ip"
 }
+
+resource "arvan_ptr_record" "terraform_floating_ip_ptr" {
+  region = var.region
+  ip     = arvan_floating_ip.terraform_floating_ip.address
+  domain = "mail.example.com"
+}
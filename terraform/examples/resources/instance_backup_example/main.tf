@@ -0,0 +1,39 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "instance_id" {
+  type        = string
+  description = "The instance to take scheduled backups of"
+}
+
+resource "arvan_instance_backup" "controller_backup" {
+  region      = var.region
+  instance_id = var.instance_id
+  name        = "controller-daily"
+  schedule    = "daily"
+  quota       = 7
+  labels      = ["controller", "built_by_terraform"]
+}
+
+output "next_backup" {
+  value = arvan_instance_backup.controller_backup.next_backup
+}
+
+output "occupancy" {
+  value = arvan_instance_backup.controller_backup.occupancy
+}
@@ -0,0 +1,47 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "boot_volume_id" {
+  type        = string
+  description = "The ID of a bootable volume, e.g. created from a personal image"
+}
+
+variable "chosen_plan_id" {
+  type        = string
+  description = "The chosen ID of plan"
+  default     = "g2-4-2-0"
+}
+
+data "arvan_security_groups" "default_security_groups" {
+  region = var.region
+}
+
+resource "arvan_abrak" "built_by_terraform" {
+  timeouts {
+    create = "1h30m"
+    update = "2h"
+    delete = "20m"
+    read   = "10m"
+  }
+  region          = var.region
+  name            = "booted_from_volume"
+  boot_volume_id  = var.boot_volume_id // conflicts with image_id and disk_size
+  flavor_id       = var.chosen_plan_id
+  ha_enabled      = true // optional, can only be set at creation time
+  security_groups = [data.arvan_security_groups.default_security_groups.groups[0].id]
+}
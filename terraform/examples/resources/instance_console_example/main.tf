@@ -0,0 +1,38 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "abrak_id" {
+  type        = string
+  description = "The ID of the instance to open a console for"
+}
+
+data "arvan_abrak_console" "console" {
+  region    = var.region
+  id        = var.abrak_id
+  log_lines = 100 // optional, default: 50, 0 skips fetching the log
+}
+
+// the url expires after a while, refresh it with terraform apply -refresh-only
+output "console_url" {
+  value     = data.arvan_abrak_console.console.url
+  sensitive = true
+}
+
+output "console_log" {
+  value = data.arvan_abrak_console.console.log
+}
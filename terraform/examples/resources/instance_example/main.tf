@@ -40,6 +40,12 @@ variable "chosen_server_group_id" {
   default     = ""
 }
 
+variable "ssh_key_names" {
+  type        = list(string)
+  description = "Names of existing SSH keys to install on the instance"
+  default     = []
+}
+
 variable "dedicated_server_label" {
   type = string
   description = "A label for chosen dedicated server"
@@ -143,18 +149,87 @@ resource "arvan_abrak" "built_by_terraform" {
   image_id        = local.chosen_image[0].id
   flavor_id       = local.selected_plan.id
   disk_size       = 25
-  server_group_id = var.chosen_server_group_id //optional
-  enable_ipv4     = true // optional, default: true
+  server_group_id = var.chosen_server_group_id //optional, changing it migrates the instance
+  migration_mode  = "cold"                     // optional, one of: cold, live
+  enable_ipv4     = true // optional, default: true, both can be toggled in place
   enable_ipv6     = true
-  networks = [
+  power_state     = "running" // optional, one of: running, stopped
+  reboot_type     = "soft"    // optional, one of: soft, hard
+  reboot_triggers = {         // optional, changing any value reboots the instance
+    config_version = "1"
+  }
+  networks = [ // order does not matter, ip and port_security_enabled change in place
     {
       network_id = arvan_network.terraform_private_network.network_id
     }
   ]
   security_groups = [arvan_security_group.terraform_security_group.id]
   volumes         = [arvan_volume.terraform_volume.id]
+  tags            = ["control"] // optional
+  ssh_key_names   = length(var.ssh_key_names) > 0 ? var.ssh_key_names : null // optional, conflicts with ssh_key_name
+
+  rebuild_on_image_change = true // optional, rebuilds in place keeping ports, volumes and floating ip
+  delete_mode             = "graceful" // optional, one of: force (default), graceful
+  deletion_protection     = false      // optional, refuses destroy when true
+
+  user_data = { // optional, rendered as #cloud-config into rendered_user_data
+    users = [
+      {
+        name                = "kolla"
+        groups              = ["docker"]
+        shell               = "/bin/bash"
+        sudo                = "ALL=(ALL) NOPASSWD:ALL"
+        ssh_authorized_keys = ["ssh-ed25519 AAAA... kolla@deploy"]
+      }
+    ]
+    packages = ["docker.io"]
+    write_files = [
+      {
+        path        = "/etc/docker/daemon.json"
+        content     = jsonencode({ "log-driver" = "journald" })
+        permissions = "0644"
+      }
+    ]
+    runcmd = ["systemctl enable --now docker", "echo tf-bootstrap-done > /dev/console"]
+  }
+  readiness = { // optional, checks run after creation, each with its own timeout (default: 5m)
+    tcp = {
+      port       = 22
+      network_id = arvan_network.terraform_private_network.network_id // optional, default: first network by network_id
+      timeout    = "5m"
+    }
+    console = {
+      marker  = "tf-bootstrap-done"
+      timeout = "10m"
+    }
+  }
+  rescue = { // optional, boots from a rescue image keeping networks and volumes attached
+    enabled  = false
+    image_id = local.chosen_image[0].id // optional, default: the rescue image of the region
+  }
 }
 
 output "instances" {
-  value = arvan_abrak.built_by_terraform
+  value     = arvan_abrak.built_by_terraform
+  sensitive = true // contains the root password
+}
+
+// computed address views, public_ipv4 and public_ipv6 are null without a public ip and
+// access_ip_v4 prefers a floating ip, then the public ipv4, then the first private network by network_id
+output "ansible_hosts" {
+  value = { for c in arvan_abrak.built_by_terraform : c.name => c.access_ip_v4 }
+}
+
+output "private_ips" {
+  value = { for c in arvan_abrak.built_by_terraform : c.name => c.private_ips_by_network }
+}
+
+data "arvan_abraks" "control_nodes" {
+  depends_on = [arvan_abrak.built_by_terraform]
+  region     = var.region
+  tags       = ["control"]
+}
+
+output "control_node_names" {
+  value = [for instance in data.arvan_abraks.control_nodes.instances : instance.name]
 }
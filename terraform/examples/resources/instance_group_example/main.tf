@@ -0,0 +1,63 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "image_id" {
+  type        = string
+  description = "The chosen image id for instances"
+}
+
+variable "security_group_id" {
+  type        = string
+  description = "The chosen security group id for instances"
+}
+
+resource "arvan_network" "terraform_private_network" {
+  region      = var.region
+  description = "Terraform-created private network"
+  name        = "tf_private_network"
+  dhcp_range = {
+    start = "10.255.255.19"
+    end   = "10.255.255.150"
+  }
+  dns_servers    = ["8.8.8.8", "1.1.1.1"]
+  enable_dhcp    = true
+  enable_gateway = true
+  cidr           = "10.255.255.0/24"
+  gateway_ip     = "10.255.255.1"
+}
+
+resource "arvan_abrak_group" "controllers" {
+  timeouts {
+    create = "30m"
+    update = "30m"
+    delete = "20m"
+  }
+  region          = var.region
+  name_template   = "controller0%d" // members are named controller01, controller02, ...
+  instance_count  = 3               // scaling creates or deletes the members with the highest index
+  image_id        = var.image_id
+  flavor_id       = "g2-16-8-0"
+  disk_size       = 100
+  networks        = [arvan_network.terraform_private_network.network_id]
+  security_groups = [var.security_group_id]
+  ssh_key_name    = "ary" // optional
+}
+
+output "controller_ips" {
+  value = { for m in arvan_abrak_group.controllers.members : m.name => m.ips[arvan_network.terraform_private_network.network_id] }
+}
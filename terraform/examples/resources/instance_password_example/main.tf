@@ -0,0 +1,64 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "pgp_public_key" {
+  type        = string
+  description = "A base64 encoded or ascii armored pgp public key"
+}
+
+data "arvan_images" "terraform_image" {
+  region     = var.region
+  image_type = "distributions"
+}
+
+data "arvan_security_groups" "default_security_groups" {
+  region = var.region
+}
+
+// only encrypted_password is stored in state, decrypt it with
+// terraform output -raw encrypted_password | base64 -d | gpg --decrypt
+resource "arvan_abrak" "encrypted_password" {
+  region          = var.region
+  name            = "encrypted-password"
+  image_id        = data.arvan_images.terraform_image.distributions[0].id
+  flavor_id       = "g2-4-2-0"
+  disk_size       = 25
+  pgp_key         = var.pgp_public_key
+  security_groups = [data.arvan_security_groups.default_security_groups.groups[0].id]
+}
+
+// no form of the password is stored in state
+resource "arvan_abrak" "unstored_password" {
+  region          = var.region
+  name            = "unstored-password"
+  image_id        = data.arvan_images.terraform_image.distributions[0].id
+  flavor_id       = "g2-4-2-0"
+  disk_size       = 25
+  store_password  = false
+  security_groups = [data.arvan_security_groups.default_security_groups.groups[0].id]
+}
+
+// fetched on every run and never persisted, requires terraform 1.10 or later
+ephemeral "arvan_abrak_password" "unstored_password" {
+  region = var.region
+  id     = arvan_abrak.unstored_password.id
+}
+
+output "encrypted_password" {
+  value = arvan_abrak.encrypted_password.encrypted_password
+}
@@ -0,0 +1,41 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+// imported by the api from an http url
+resource "arvan_private_image" "from_url" {
+  region      = var.region
+  name        = "ubuntu-22.04-cloudimg"
+  source_url  = "https://cloud-images.ubuntu.com/jammy/current/jammy-server-cloudimg-amd64.img"
+  disk_format = "qcow2"
+}
+
+// uploaded from the machine running terraform, a new file checksum replaces the image
+resource "arvan_private_image" "hardened_base" {
+  timeouts {
+    create = "2h"
+  }
+  region      = var.region
+  name        = "hardened-base"
+  source_file = "${path.module}/images/hardened-base.qcow2"
+  checksum    = filemd5("${path.module}/images/hardened-base.qcow2")
+  disk_format = "qcow2"
+}
+
+output "hardened_base_image_id" {
+  value = arvan_private_image.hardened_base.id
+}
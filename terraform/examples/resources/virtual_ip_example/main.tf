@@ -0,0 +1,60 @@
+terraform {
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+variable "image_id" {
+  type        = string
+  description = "The chosen image id for instances"
+}
+
+resource "arvan_network" "terraform_private_network" {
+  region      = var.region
+  description = "Terraform-created private network"
+  name        = "tf_private_network"
+  dhcp_range = {
+    start = "10.255.255.19"
+    end   = "10.255.255.150"
+  }
+  dns_servers    = ["8.8.8.8", "1.1.1.1"]
+  enable_dhcp    = true
+  enable_gateway = false
+  cidr           = "10.255.255.0/24"
+}
+
+// reserves 10.255.255.250 so dhcp never hands it out to an instance
+resource "arvan_virtual_ip" "keepalived_vip" {
+  region     = var.region
+  name       = "tf_keepalived_vip"
+  network_id = arvan_network.terraform_private_network.network_id
+  ip         = "10.255.255.250"
+}
+
+resource "arvan_abrak" "controllers" {
+  count           = 3
+  region          = var.region
+  name            = "controller0${count.index + 1}"
+  image_id        = var.image_id
+  flavor_id       = "g1-2-1-0"
+  disk_size       = 25
+  security_groups = []
+  networks = [
+    {
+      network_id            = arvan_network.terraform_private_network.network_id
+      allowed_address_pairs = [arvan_virtual_ip.keepalived_vip.ip]
+    }
+  ]
+}
@@ -0,0 +1,44 @@
+terraform {
+  // moving state between resource types needs terraform >= 1.8
+  required_version = ">= 1.8"
+  required_providers {
+    arvan = {
+      source = "terraform.arvancloud.ir/arvancloud/iaas"
+    }
+  }
+}
+
+provider "arvan" {
+  api_key = "apikey"
+}
+
+variable "region" {
+  type        = string
+  description = "The chosen region for resources"
+  default     = "ir-thr-ba1"
+}
+
+// was: resource "arvan_volume" "terraform_volume"
+resource "arvan_volume_v2" "terraform_volume" {
+  region = var.region
+  name   = "tf_volume"
+  size   = 9
+  type   = "hdd-g1"
+}
+
+moved {
+  from = arvan_volume.terraform_volume
+  to   = arvan_volume_v2.terraform_volume
+}
+
+// was: resource "arvan_volume_snapshot" "terraform_volume_snapshot"
+resource "arvan_volume_snapshot_v2" "terraform_volume_snapshot" {
+  region    = var.region
+  name      = "tf_volume_snapshot"
+  volume_id = arvan_volume_v2.terraform_volume.id
+}
+
+moved {
+  from = arvan_volume_snapshot.terraform_volume_snapshot
+  to   = arvan_volume_snapshot_v2.terraform_volume_snapshot
+}
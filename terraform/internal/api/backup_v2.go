@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 )
 
@@ -402,3 +404,97 @@ func (b *BackupV2Client) GetSnapshotDetails(ctx context.Context, region, snapsho
 	}
 	return &ret, nil
 }
+
+type InstanceBackupRequest struct {
+	InstanceID string   `json:"instance_id,omitempty"`
+	BackupName string   `json:"backup_name"`
+	Schedule   string   `json:"schedule"`
+	Quota      int      `json:"quota"`
+	Labels     []string `json:"labels"`
+}
+
+type DeleteInstanceBackupsRequest struct {
+	InstanceIDs []string `json:"instance_ids"`
+}
+
+type InstanceBackupResponse struct {
+	Code    int        `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+	Items   [][]string `json:"errors,omitempty"`
+}
+
+// decodeBackupResponse returns the errors the api reports inside a 2xx response as a
+// ResponseError, a rejected backup change would otherwise look like a success
+func decodeBackupResponse(data []byte, uri string) (*InstanceBackupResponse, error) {
+	var ret InstanceBackupResponse
+	err := json.Unmarshal(data, &ret)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []string
+	for _, item := range ret.Items {
+		errs = append(errs, strings.Join(item, ": "))
+	}
+	if len(errs) == 0 && ret.Code < 400 {
+		return &ret, nil
+	}
+
+	msg := ret.Message
+	if len(errs) > 0 {
+		msg = strings.TrimPrefix(msg+": "+strings.Join(errs, ", "), ": ")
+	}
+	code := ret.Code
+	if code == 0 {
+		code = http.StatusUnprocessableEntity
+	}
+	return nil, &ResponseError{
+		Code:    code,
+		URL:     uri,
+		Message: msg,
+		Errors:  errs,
+	}
+}
+
+func (b *BackupV2Client) CreateBackup(ctx context.Context, region string, req *InstanceBackupRequest) (*InstanceBackupResponse, error) {
+	uri := fmt.Sprintf("%s/backup/%s/create", bpV2, region)
+	data, err := b.r.DoRequest(ctx, "POST", uri, req)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBackupResponse(data, uri)
+}
+
+func (b *BackupV2Client) UpdateBackup(ctx context.Context, region, instanceID string, req *InstanceBackupRequest) (*InstanceBackupResponse, error) {
+	uri := fmt.Sprintf("%s/backup/%s/%s", bpV2, region, instanceID)
+	data, err := b.r.DoRequest(ctx, "PUT", uri, req)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBackupResponse(data, uri)
+}
+
+func (b *BackupV2Client) DeleteBackups(ctx context.Context, region string, req *DeleteInstanceBackupsRequest) (*InstanceBackupResponse, error) {
+	uri := fmt.Sprintf("%s/backup/%s/delete", bpV2, region)
+	data, err := b.r.DoRequest(ctx, "POST", uri, req)
+	if err != nil {
+		return nil, err
+	}
+	return decodeBackupResponse(data, uri)
+}
+
+func (b *BackupV2Client) GetInstanceBackup(ctx context.Context, region, instanceID string) (*BackupListData, error) {
+	all, err := b.ListBackups(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range all.Data {
+		if x.InstanceID == instanceID {
+			return &x, nil
+		}
+	}
+	return nil, &ResponseError{
+		Code:    404,
+		Message: "backup not found",
+	}
+}
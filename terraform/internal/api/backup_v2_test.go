@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+)
+
+func TestDecodeBackupResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{"accepted", `{"message":"backup created"}`, ""},
+		{"rejected", `{"message":"validation failed","errors":[["quota","must be at most 10"]]}`, "validation failed: quota: must be at most 10"},
+		{"errors only", `{"errors":[["schedule","invalid"]]}`, "schedule: invalid"},
+		{"error code", `{"code":409,"message":"backup already exists"}`, "backup already exists"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeBackupResponse([]byte(tt.body), "backup/ir-thr-ba1/create")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+				return
+			}
+			respErr, ok := err.(*ResponseError)
+			if !ok || respErr.Message != tt.wantErr {
+				t.Fatalf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
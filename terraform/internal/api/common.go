@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"time"
 )
@@ -41,13 +42,19 @@ func (r *ResponseError) Error() string {
 
 type Requester struct {
 	client *http.Client
-	apiKey string
+	// uploadClient shares the transport of client without its fixed timeout, the timeout
+	// covers sending the body so large uploads are bounded by their context instead
+	uploadClient *http.Client
+	apiKey       string
 }
 
 func NewRequester(c *http.Client, apiKey string) *Requester {
+	upload := *c
+	upload.Timeout = 0
 	return &Requester{
-		client: c,
-		apiKey: apiKey,
+		client:       c,
+		uploadClient: &upload,
+		apiKey:       apiKey,
 	}
 }
 
@@ -73,11 +80,55 @@ func (r *Requester) DoRequest(ctx context.Context, method, uri string, input int
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("Authorization", r.apiKey)
 	req.Header.Add("Content-Type", "application/json")
+
+	return r.do(r.client, req, uri)
+}
+
+// DoMultipartRequest streams file as a multipart/form-data upload alongside the given
+// form fields, the body is written through a pipe so large images are never buffered
+// in memory. The upload is only bounded by ctx
+func (r *Requester) DoMultipartRequest(ctx context.Context, method, uri string, fields map[string]string, fileField, fileName string, file io.Reader) ([]byte, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		for k, v := range fields {
+			if err := mw.WriteField(k, v); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		fw, err := mw.CreateFormFile(fileField, fileName)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if _, err = io.Copy(fw, file); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		pw.CloseWithError(mw.Close())
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, method, uri, pr)
+	if err != nil {
+		pr.Close()
+		return nil, err
+	}
+	req.Header.Add("Content-Type", mw.FormDataContentType())
+
+	ret, err := r.do(r.uploadClient, req, uri)
+	// unblock the writer if the server answered before consuming the whole body
+	pr.Close()
+	return ret, err
+}
+
+func (r *Requester) do(client *http.Client, req *http.Request, uri string) ([]byte, error) {
+	req.Header.Add("Authorization", r.apiKey)
 	req.Header.Add("Accept-Language", "en")
 
-	resp, err := r.client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +185,8 @@ type Client struct {
 	ServerGroup     *ServerGroupClient
 	DedicatedServer *DedicatedServerClient
 	FirewallV2      *FirewallV2Client
+	PTR             *PTRClient
+	Tag             *TagClient
 }
 
 func NewClient(apiKey string) *Client {
@@ -155,6 +208,8 @@ func NewClient(apiKey string) *Client {
 	bV2 := NewBackupV2Client(r)
 	serverGroupC := NewServerGroupClient(r)
 	dsClient := NewDedicatedServerClient(r)
+	ptrC := NewPTRClient(r)
+	tagC := NewTagClient(r)
 	ret := &Client{
 		Img:             imgC,
 		Pln:             plnC,
@@ -170,6 +225,8 @@ func NewClient(apiKey string) *Client {
 		FirewallV2:      fwv2C,
 		ServerGroup:     serverGroupC,
 		DedicatedServer: dsClient,
+		PTR:             ptrC,
+		Tag:             tagC,
 	}
 	return ret
 }
@@ -203,6 +260,7 @@ type NetworkAttachment struct {
 	SubnetID            string
 	IP                  string
 	PortID              string
+	PTR                 string
 	IsPublic            bool
 	PortSecurityEnabled bool
 }
@@ -274,6 +332,7 @@ func (c *Client) GetNetworkAttachments(ctx context.Context, detail *ServerDetail
 								attachment.IP = i.IP
 								attachment.IsPublic = i.Public
 								attachment.PortSecurityEnabled = i.PortSecurityEnabled
+								attachment.PTR = i.PTRRecord()
 								break outer
 							}
 
@@ -323,6 +382,7 @@ func (c *Client) FillNetworkData(ctx context.Context, netIds []string, region, s
 							attachment.IP = i.IP
 							attachment.IsPublic = i.Public
 							attachment.PortSecurityEnabled = i.PortSecurityEnabled
+							attachment.PTR = i.PTRRecord()
 							break outer
 						}
 
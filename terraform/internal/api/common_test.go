@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoMultipartRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "apikey test" {
+			t.Errorf("missing authorization header")
+		}
+		f, hdr, err := req.FormFile("file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		content, _ := io.ReadAll(f)
+		if string(content) != "image-content" || hdr.Filename != "base.qcow2" {
+			t.Errorf("unexpected file %s: %q", hdr.Filename, content)
+		}
+		if req.FormValue("disk_format") != "qcow2" {
+			t.Errorf("unexpected disk_format %q", req.FormValue("disk_format"))
+		}
+		w.Write([]byte(`{"data":{"id":"img-1"}}`))
+	}))
+	defer srv.Close()
+
+	r := NewRequester(srv.Client(), "apikey test")
+	ret, err := r.DoMultipartRequest(context.Background(), "POST", srv.URL, map[string]string{"disk_format": "qcow2"}, "file", "base.qcow2", strings.NewReader("image-content"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(ret) != `{"data":{"id":"img-1"}}` {
+		t.Errorf("unexpected response %s", ret)
+	}
+}
+
+func TestDoMultipartRequestError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"invalid disk format"}`))
+	}))
+	defer srv.Close()
+
+	r := NewRequester(srv.Client(), "apikey test")
+	_, err := r.DoMultipartRequest(context.Background(), "POST", srv.URL, nil, "file", "base.img", strings.NewReader("x"))
+	respErr, ok := err.(*ResponseError)
+	if !ok || respErr.Code != http.StatusUnprocessableEntity || respErr.Message != "invalid disk format" {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+// slowReader returns one byte per delay
+type slowReader struct {
+	remaining int
+	delay     time.Duration
+}
+
+func (s *slowReader) Read(p []byte) (int, error) {
+	if s.remaining == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(s.delay)
+	s.remaining--
+	p[0] = 'x'
+	return 1, nil
+}
+
+func TestDoMultipartRequestOutlivesClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// the cancelled upload below never reaches the end of the body
+		f, _, err := req.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		content, _ := io.ReadAll(f)
+		if len(content) != 6 {
+			t.Errorf("expected 6 bytes, got %d", len(content))
+		}
+		w.Write([]byte(`{"data":{"id":"img-1"}}`))
+	}))
+	defer srv.Close()
+
+	client := srv.Client()
+	client.Timeout = 100 * time.Millisecond
+	r := NewRequester(client, "apikey test")
+
+	_, err := r.DoMultipartRequest(context.Background(), "POST", srv.URL, nil, "file", "big.qcow2", &slowReader{remaining: 6, delay: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("upload longer than the client timeout failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = r.DoMultipartRequest(ctx, "POST", srv.URL, nil, "file", "big.qcow2", &slowReader{remaining: 6, delay: 50 * time.Millisecond})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the upload to be bounded by its context, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 )
 
 type ImageDistroItem struct {
@@ -53,6 +54,13 @@ type PrivateImage struct {
 	RealSize        int64  `json:"real_size"`
 	Size            int64  `json:"size"`
 	Status          string `json:"status"`
+	Progress        int    `json:"progress"`
+}
+
+type ImportImageRequest struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	DiskFormat string `json:"disk_format"`
 }
 
 type ImageListItem struct {
@@ -119,3 +127,41 @@ func (i *ImageClient) GetPrivateImageByID(ctx context.Context, region, id string
 		Message: "private image not found",
 	}
 }
+
+func (i *ImageClient) ImportImageFromURL(ctx context.Context, region string, req *ImportImageRequest) (*PrivateImage, error) {
+	uri := fmt.Sprintf("%s/%s/images/import", basePath, region)
+	resp, err := i.requester.DoRequest(ctx, "POST", uri, req)
+	if err != nil {
+		return nil, err
+	}
+	var ret DataResponse[PrivateImage]
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret.Data, nil
+}
+
+func (i *ImageClient) UploadImage(ctx context.Context, region, name, diskFormat, fileName string, file io.Reader) (*PrivateImage, error) {
+	uri := fmt.Sprintf("%s/%s/images/upload", basePath, region)
+	fields := map[string]string{
+		"name":        name,
+		"disk_format": diskFormat,
+	}
+	resp, err := i.requester.DoMultipartRequest(ctx, "POST", uri, fields, "file", fileName, file)
+	if err != nil {
+		return nil, err
+	}
+	var ret DataResponse[PrivateImage]
+	err = json.Unmarshal(resp, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret.Data, nil
+}
+
+func (i *ImageClient) DeletePrivateImage(ctx context.Context, region, id string) error {
+	uri := fmt.Sprintf("%s/%s/images/%s", basePath, region, id)
+	_, err := i.requester.DoRequest(ctx, "DELETE", uri, nil)
+	return err
+}
@@ -162,6 +162,14 @@ func (i *InstanceClient) DeleteInstance(ctx context.Context, region, id string)
 	return err
 }
 
+// GracefulDeleteInstance deletes the instance without forcing, resources still bound to
+// it are not cleaned up implicitly
+func (i *InstanceClient) GracefulDeleteInstance(ctx context.Context, region, id string) error {
+	url := fmt.Sprintf("%s/%s/servers/%s", basePath, region, id)
+	_, err := i.requester.DoRequest(ctx, "DELETE", url, nil)
+	return err
+}
+
 func (i *InstanceClient) ResizeInstance(ctx context.Context, region, id, flavorID string) error {
 	type resizeReq struct {
 		FlavorID string `json:"flavor_id"`
@@ -211,6 +219,39 @@ func (i *InstanceClient) HardRebootInstance(ctx context.Context, region, id stri
 	return err
 }
 
+// RescueInstance boots the instance from imageID, or the default rescue image when it is
+// empty, with the original disk attached
+func (i *InstanceClient) RescueInstance(ctx context.Context, region, id, imageID string) error {
+	type rescueReq struct {
+		ImageID string `json:"image_id,omitempty"`
+	}
+	url := fmt.Sprintf("%s/%s/servers/%s/rescue", basePath, region, id)
+	req := rescueReq{
+		ImageID: imageID,
+	}
+	_, err := i.requester.DoRequest(ctx, "POST", url, &req)
+	return err
+}
+
+func (i *InstanceClient) UnrescueInstance(ctx context.Context, region, id string) error {
+	url := fmt.Sprintf("%s/%s/servers/%s/unrescue", basePath, region, id)
+	_, err := i.requester.DoRequest(ctx, "POST", url, nil)
+	return err
+}
+
+type InstanceMigrateRequest struct {
+	Live              bool   `json:"live"`
+	ServerGroupID     string `json:"server_group_id,omitempty"`
+	DedicatedServerID string `json:"dedicated_server_id,omitempty"`
+}
+
+// MigrateInstance moves the instance to another host keeping its id, addresses and volumes
+func (i *InstanceClient) MigrateInstance(ctx context.Context, region, id string, req *InstanceMigrateRequest) error {
+	url := fmt.Sprintf("%s/%s/servers/%s/migrate", basePath, region, id)
+	_, err := i.requester.DoRequest(ctx, "POST", url, req)
+	return err
+}
+
 func (i *InstanceClient) RenameInstance(ctx context.Context, region, id, name string) error {
 	type renameReq struct {
 		Name string `json:"name"`
@@ -222,3 +263,76 @@ func (i *InstanceClient) RenameInstance(ctx context.Context, region, id, name st
 	_, err := i.requester.DoRequest(ctx, "POST", url, &req)
 	return err
 }
+
+func (i *InstanceClient) GetConsoleLog(ctx context.Context, region, id string) (string, error) {
+	type consoleLogResponse struct {
+		Data struct {
+			Output string `json:"output"`
+		} `json:"data"`
+	}
+	url := fmt.Sprintf("%s/%s/servers/%s/console-log", basePath, region, id)
+
+	data, err := i.requester.DoRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	var resp consoleLogResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return "", err
+	}
+	return resp.Data.Output, nil
+}
+
+type ServerConsole struct {
+	URL string `json:"url"`
+}
+
+// GetVNCConsole returns a noVNC console for the instance, the url is only valid for a
+// limited time
+func (i *InstanceClient) GetVNCConsole(ctx context.Context, region, id string) (*ServerConsole, error) {
+	type consoleResponse struct {
+		Data ServerConsole `json:"data"`
+	}
+	url := fmt.Sprintf("%s/%s/servers/%s/vnc", basePath, region, id)
+
+	data, err := i.requester.DoRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp consoleResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Data, nil
+}
+
+func (i *InstanceClient) RebuildInstance(ctx context.Context, region, id, imageID, initScript string) (*ServerDetail, error) {
+	type rebuildReq struct {
+		ImageID    string `json:"image_id"`
+		InitScript string `json:"init_script,omitempty"`
+	}
+	type rebuildResponse struct {
+		Data *ServerDetail `json:"data"`
+	}
+	url := fmt.Sprintf("%s/%s/servers/%s/rebuild", basePath, region, id)
+	req := rebuildReq{
+		ImageID:    imageID,
+		InitScript: initScript,
+	}
+
+	data, err := i.requester.DoRequest(ctx, "POST", url, &req)
+	if err != nil {
+		return nil, err
+	}
+	var resp rebuildResponse
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Data == nil {
+		return &ServerDetail{ID: id}, nil
+	}
+	return resp.Data, nil
+}
@@ -33,6 +33,14 @@ type FullIP struct {
 	SecurityGroups      []PortSecGroupData `json:"security_groups"`
 }
 
+// PTRRecord returns the reverse dns domain of the ip, the api sends null when none is set
+func (f *FullIP) PTRRecord() string {
+	if ptr, ok := f.PTR.(string); ok {
+		return ptr
+	}
+	return ""
+}
+
 type PublicIP struct {
 	SubnetID  string `json:"subnet_id"`
 	IPAddress string `json:"ip_address"`
@@ -158,16 +166,29 @@ type AttachServerToNetworkRequest struct {
 	EnablePortSecurity bool   `json:"enablePortSecurity"`
 }
 
+type AllowedAddressPair struct {
+	IPAddress  string `json:"ip_address"`
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
 type AttachedPort struct {
-	AdminStateUp    bool   `json:"admin_state_up"`
-	IsRegionNetwork bool   `json:"is_region_network"`
-	Status          string `json:"status"`
-	MacAddr         string `json:"mac_addr"`
-	IPAddress       string `json:"ip_address"`
-	ID              string `json:"id"`
-	NetworkID       string `json:"network_id"`
-	DeviceID        string `json:"device_id"`
-	SubnetID        string `json:"subnet_id"`
+	AdminStateUp        bool                 `json:"admin_state_up"`
+	IsRegionNetwork     bool                 `json:"is_region_network"`
+	Status              string               `json:"status"`
+	MacAddr             string               `json:"mac_addr"`
+	IPAddress           string               `json:"ip_address"`
+	ID                  string               `json:"id"`
+	Name                string               `json:"name"`
+	NetworkID           string               `json:"network_id"`
+	DeviceID            string               `json:"device_id"`
+	SubnetID            string               `json:"subnet_id"`
+	AllowedAddressPairs []AllowedAddressPair `json:"allowed_address_pairs"`
+}
+
+type CreatePortRequest struct {
+	Name     string `json:"name"`
+	SubnetID string `json:"subnet_id"`
+	IP       string `json:"ip,omitempty"`
 }
 
 type SubnetClient struct {
@@ -341,3 +362,64 @@ func (s *SubnetClient) EnablePortSecurity(ctx context.Context, region, networkID
 	_, err := s.requester.DoRequest(ctx, "PATCH", url, &enableReq{networkID})
 	return err
 }
+
+// CreatePort reserves a port in the given network without attaching it to any
+// server, it is used for virtual ips that float between instances
+func (s *SubnetClient) CreatePort(ctx context.Context, region, networkID string, req *CreatePortRequest) (*AttachedPort, error) {
+	url := fmt.Sprintf("%s/%s/networks/%s/ports", basePath, region, networkID)
+	data, err := s.requester.DoRequest(ctx, "POST", url, req)
+	if err != nil {
+		return nil, err
+	}
+	var resp DataResponse[*AttachedPort]
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (s *SubnetClient) GetPort(ctx context.Context, region, portID string) (*AttachedPort, error) {
+	url := fmt.Sprintf("%s/%s/ports/%s", basePath, region, portID)
+	data, err := s.requester.DoRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp DataResponse[*AttachedPort]
+	err = json.Unmarshal(data, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data, nil
+}
+
+func (s *SubnetClient) DeletePort(ctx context.Context, region, portID string) error {
+	url := fmt.Sprintf("%s/%s/ports/%s", basePath, region, portID)
+	_, err := s.requester.DoRequest(ctx, "DELETE", url, nil)
+	return err
+}
+
+// UpdatePortIP moves the fixed ip of a port to ip on subnetID keeping the port attached
+func (s *SubnetClient) UpdatePortIP(ctx context.Context, region, portID, subnetID, ip string) error {
+	type fixedIPReq struct {
+		SubnetID  string `json:"subnet_id"`
+		IPAddress string `json:"ip_address"`
+	}
+	url := fmt.Sprintf("%s/%s/ports/%s/fixedIps", basePath, region, portID)
+	_, err := s.requester.DoRequest(ctx, "PATCH", url, &fixedIPReq{subnetID, ip})
+	return err
+}
+
+// SetAllowedAddressPairs replaces the allowed address pairs of a port, an empty
+// list removes all of them
+func (s *SubnetClient) SetAllowedAddressPairs(ctx context.Context, region, portID string, pairs []AllowedAddressPair) error {
+	type pairsReq struct {
+		AllowedAddressPairs []AllowedAddressPair `json:"allowed_address_pairs"`
+	}
+	if pairs == nil {
+		pairs = []AllowedAddressPair{}
+	}
+	url := fmt.Sprintf("%s/%s/ports/%s/allowedAddressPairs", basePath, region, portID)
+	_, err := s.requester.DoRequest(ctx, "PATCH", url, &pairsReq{pairs})
+	return err
+}
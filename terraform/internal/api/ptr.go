@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type PTRRecord struct {
+	IP     string `json:"ip"`
+	Domain string `json:"domain"`
+}
+
+type PTRClient struct {
+	requester *Requester
+}
+
+func NewPTRClient(r *Requester) *PTRClient {
+	return &PTRClient{
+		requester: r,
+	}
+}
+
+// SetPTR creates or replaces the reverse dns record of a public or floating ip
+func (p *PTRClient) SetPTR(ctx context.Context, region, ip, domain string) error {
+	url := fmt.Sprintf("%s/%s/ptr", basePath, region)
+	_, err := p.requester.DoRequest(ctx, "POST", url, &PTRRecord{
+		IP:     ip,
+		Domain: domain,
+	})
+	return err
+}
+
+func (p *PTRClient) GetPTR(ctx context.Context, region, ip string) (*PTRRecord, error) {
+	url := fmt.Sprintf("%s/%s/ptr/%s", basePath, region, ip)
+	data, err := p.requester.DoRequest(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var ret DataResponse[PTRRecord]
+	err = json.Unmarshal(data, &ret)
+	if err != nil {
+		return nil, err
+	}
+	if ret.Data.Domain == "" {
+		return nil, &ResponseError{
+			Code:    404,
+			URL:     url,
+			Message: "ptr record not found",
+		}
+	}
+	return &ret.Data, nil
+}
+
+func (p *PTRClient) DeletePTR(ctx context.Context, region, ip string) error {
+	url := fmt.Sprintf("%s/%s/ptr/%s", basePath, region, ip)
+	_, err := p.requester.DoRequest(ctx, "DELETE", url, nil)
+	return err
+}
@@ -1,6 +1,48 @@
 package api
 
+import (
+	"context"
+	"fmt"
+)
+
 type Tag struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 }
+
+type tagInstanceRequest struct {
+	TagName      string `json:"tag_name,omitempty"`
+	InstanceID   string `json:"instance_id"`
+	InstanceType string `json:"instance_type"`
+}
+
+type TagClient struct {
+	requester *Requester
+}
+
+func NewTagClient(r *Requester) *TagClient {
+	return &TagClient{
+		requester: r,
+	}
+}
+
+// AttachTag attaches the tag with the given name to a server, the tag is created
+// when it does not exist yet
+func (t *TagClient) AttachTag(ctx context.Context, region, name, serverID string) error {
+	url := fmt.Sprintf("%s/%s/tags", basePath, region)
+	_, err := t.requester.DoRequest(ctx, "POST", url, &tagInstanceRequest{
+		TagName:      name,
+		InstanceID:   serverID,
+		InstanceType: "server",
+	})
+	return err
+}
+
+func (t *TagClient) DetachTag(ctx context.Context, region, tagID, serverID string) error {
+	url := fmt.Sprintf("%s/%s/tags/%s/detach", basePath, region, tagID)
+	_, err := t.requester.DoRequest(ctx, "POST", url, &tagInstanceRequest{
+		InstanceID:   serverID,
+		InstanceType: "server",
+	})
+	return err
+}
@@ -141,6 +141,43 @@ func (v *VolumeClient) GetServerVolumes(ctx context.Context, region, serverID st
 	return ret, nil
 }
 
+// rootDevices are the device names the root disk of an instance is attached as
+var rootDevices = map[string]bool{
+	"/dev/vda":  true,
+	"/dev/sda":  true,
+	"/dev/xvda": true,
+}
+
+// serverDataVolumes returns the volumes attached to serverID leaving out bootVolumeID and
+// the root disk of instances created from an image
+func serverDataVolumes(volumes []*VolumeDetails, serverID, bootVolumeID string) []string {
+	var ret []string
+	for _, x := range volumes {
+		if x.ID == bootVolumeID {
+			continue
+		}
+		for _, a := range x.Attachments {
+			if a.ServerID != serverID {
+				continue
+			}
+			if !rootDevices[a.Device] {
+				ret = append(ret, x.ID)
+			}
+			break
+		}
+	}
+	return ret
+}
+
+// GetServerDataVolumes is GetServerVolumes without the boot or root volume of the server
+func (v *VolumeClient) GetServerDataVolumes(ctx context.Context, region, serverID, bootVolumeID string) ([]string, error) {
+	allV, err := v.ListVolumes(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	return serverDataVolumes(allV, serverID, bootVolumeID), nil
+}
+
 func (v *VolumeClient) GetVolume(ctx context.Context, region, id string) (*VolumeDetails, error) {
 	l, err := v.ListVolumes(ctx, region)
 	if err != nil {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"reflect"
 	"testing"
 )
 
@@ -43,3 +44,27 @@ func TestGetAttachments(t *testing.T) {
 
 	}
 }
+
+func TestServerDataVolumes(t *testing.T) {
+	attached := func(id, server, device string) *VolumeDetails {
+		return &VolumeDetails{ID: id, Attachments: []Attachment{{ServerID: server, Device: device}}}
+	}
+	volumes := []*VolumeDetails{
+		attached("root", "srv", "/dev/vda"),
+		attached("data-1", "srv", "/dev/vdb"),
+		attached("other", "srv-2", "/dev/vdb"),
+		{ID: "free"},
+	}
+
+	got := serverDataVolumes(volumes, "srv", "")
+	if !reflect.DeepEqual(got, []string{"data-1"}) {
+		t.Errorf("expected only the data volume, got %v", got)
+	}
+
+	// instances booted from a volume, as detached by graceful delete
+	volumes = append(volumes, attached("boot", "srv-boot", "/dev/vdb"), attached("data-2", "srv-boot", "/dev/vdc"))
+	got = serverDataVolumes(volumes, "srv-boot", "boot")
+	if !reflect.DeepEqual(got, []string{"data-2"}) {
+		t.Errorf("expected the boot volume to be skipped, got %v", got)
+	}
+}
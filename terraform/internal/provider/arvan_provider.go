@@ -3,15 +3,19 @@ package provider
 import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"terraform-provider-hashicups-pf/internal/api"
 	"terraform-provider-hashicups-pf/internal/provider/ds"
+	"terraform-provider-hashicups-pf/internal/provider/ep"
 	"terraform-provider-hashicups-pf/internal/provider/models"
 	"terraform-provider-hashicups-pf/internal/provider/rs"
 )
 
+var _ provider.ProviderWithEphemeralResources = &ArvanProvider{}
+
 type ArvanProvider struct {
 	version string
 }
@@ -50,6 +54,7 @@ func (p *ArvanProvider) Configure(ctx context.Context, req provider.ConfigureReq
 	apiC := api.NewClient(data.ApiKey.ValueString())
 	resp.ResourceData = apiC
 	resp.DataSourceData = apiC
+	resp.EphemeralResourceData = apiC
 
 }
 
@@ -66,6 +71,11 @@ func (p *ArvanProvider) Resources(ctx context.Context) []func() resource.Resourc
 		rs.NewVolumeV2Resource,
 		rs.NewVolumeSnapshotV2Resource,
 		rs.NewInstanceSnapshotResource,
+		rs.NewVirtualIPResource,
+		rs.NewInstanceBackupResource,
+		rs.NewPrivateImageResource,
+		rs.NewPTRRecordResource,
+		rs.NewInstanceGroupResource,
 	}
 }
 
@@ -87,6 +97,13 @@ func (p *ArvanProvider) DataSources(ctx context.Context) []func() datasource.Dat
 		ds.NewInstanceSnapshotDatasourceV2,
 		ds.NewServerGroupDatasource,
 		ds.NewDedicatedServerDatasource,
+		ds.NewInstanceConsoleDatasource,
+	}
+}
+
+func (p *ArvanProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		ep.NewInstancePasswordEphemeralResource,
 	}
 }
 
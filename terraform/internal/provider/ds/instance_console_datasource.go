@@ -0,0 +1,95 @@
+package ds
+
+import (
+	"context"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const defaultConsoleLogLines = 50
+
+type InstanceConsoleDatasource struct {
+	client *api.Client
+}
+
+func (i *InstanceConsoleDatasource) SetAPIClient(client *api.Client) {
+	i.client = client
+}
+
+func (i *InstanceConsoleDatasource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_abrak_console"
+}
+
+func (i *InstanceConsoleDatasource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	misc.ConfigureDatasource(ctx, &req, resp, i)
+}
+
+func (i *InstanceConsoleDatasource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+			},
+			"id": schema.StringAttribute{
+				Required: true,
+			},
+			"log_lines": schema.Int64Attribute{
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"url": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"log": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (i *InstanceConsoleDatasource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.TFInstanceConsoleModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, id := data.Region.ValueString(), data.ID.ValueString()
+	console, err := i.client.Instance.GetVNCConsole(ctx, region, id)
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching instance console", err.Error())
+		return
+	}
+	data.URL = types.StringValue(console.URL)
+
+	lines := defaultConsoleLogLines
+	if !data.LogLines.IsNull() {
+		lines = int(data.LogLines.ValueInt64())
+	}
+	data.Log = types.StringValue("")
+	if lines > 0 {
+		log, err := i.client.Instance.GetConsoleLog(ctx, region, id)
+		if err != nil {
+			resp.Diagnostics.AddError("error fetching instance console log", err.Error())
+			return
+		}
+		data.Log = types.StringValue(utl.TailLines(log, lines))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func NewInstanceConsoleDatasource() datasource.DataSource {
+	return &InstanceConsoleDatasource{}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -38,6 +39,16 @@ func (i *InstanceDatasource) Schema(ctx context.Context, req datasource.SchemaRe
 			"region": schema.StringAttribute{
 				Required: true,
 			},
+			"tags": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"pgp_key": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					utl.PGPKeyValidator(),
+				},
+			},
 			"instances": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
@@ -74,8 +85,12 @@ func (i *InstanceDatasource) Schema(ctx context.Context, req datasource.SchemaRe
 							Computed: true,
 						},
 						"password": schema.StringAttribute{
+							Computed:  true,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"encrypted_password": schema.StringAttribute{
 							Computed: true,
-							Optional: true,
 						},
 						"task_state": schema.StringAttribute{
 							Computed: true,
@@ -195,17 +210,37 @@ func (i *InstanceDatasource) Read(ctx context.Context, req datasource.ReadReques
 		return
 	}
 
+	tagFilter := utl.ListToSet(tfData.Tags)
+
 	for _, s := range apiResp {
+		if !hasAllTags(s.Tags, tagFilter) {
+			continue
+		}
+
 		tfInst := models.TFInstanceDetails{
 			ID:        types.StringValue(s.ID),
 			Name:      types.StringValue(s.Name),
 			Status:    types.StringValue(s.Status),
 			Created:   types.StringValue(s.Created),
-			Password:  types.StringValue(s.Password),
 			KeyName:   types.StringValue(s.KeyName),
 			HAEnabled: types.BoolValue(s.HAEnabled),
 		}
 
+		// with a pgp key only the encrypted password ends up in state
+		tfInst.Password = types.StringValue(s.Password)
+		tfInst.EncryptedPassword = types.StringNull()
+		if !tfData.PGPKey.IsNull() {
+			tfInst.Password = types.StringNull()
+			if s.Password != "" {
+				encrypted, err := utl.EncryptWithPGPKey(tfData.PGPKey.ValueString(), s.Password)
+				if err != nil {
+					resp.Diagnostics.AddError("error encrypting password", err.Error())
+					return
+				}
+				tfInst.EncryptedPassword = types.StringValue(encrypted)
+			}
+		}
+
 		if s.TaskState != nil {
 			tfInst.TaskState = types.StringValue(*s.TaskState)
 		}
@@ -297,6 +332,16 @@ func (i *InstanceDatasource) Read(ctx context.Context, req datasource.ReadReques
 
 }
 
+func hasAllTags(tags []*api.Tag, filter map[string]bool) bool {
+	var found int
+	for _, t := range tags {
+		if filter[t.Name] {
+			found++
+		}
+	}
+	return found == len(filter)
+}
+
 func NewInstanceDatasource() datasource.DataSource {
 	return &InstanceDatasource{}
 }
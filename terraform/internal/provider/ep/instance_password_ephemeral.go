@@ -0,0 +1,74 @@
+package ep
+
+import (
+	"context"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ ephemeral.EphemeralResourceWithConfigure = &InstancePasswordEphemeralResource{}
+
+// InstancePasswordEphemeralResource fetches the root password of an instance without
+// persisting it in plan or state
+type InstancePasswordEphemeralResource struct {
+	client *api.Client
+}
+
+func (i *InstancePasswordEphemeralResource) SetAPIClient(client *api.Client) {
+	i.client = client
+}
+
+func (i *InstancePasswordEphemeralResource) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_abrak_password"
+}
+
+func (i *InstancePasswordEphemeralResource) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	misc.ConfigureEphemeralResource(ctx, &req, resp, i)
+}
+
+func (i *InstancePasswordEphemeralResource) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+			},
+			"id": schema.StringAttribute{
+				Required: true,
+			},
+			"password": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func (i *InstancePasswordEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data models.TFInstancePasswordModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	detail, err := i.client.Instance.GetInstance(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching instance", err.Error())
+		return
+	}
+	if detail.Password == "" {
+		resp.Diagnostics.AddError("instance password not available", "the api does not return a password for this instance")
+		return
+	}
+
+	data.Password = types.StringValue(detail.Password)
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+func NewInstancePasswordEphemeralResource() ephemeral.EphemeralResource {
+	return &InstancePasswordEphemeralResource{}
+}
@@ -3,6 +3,7 @@ package misc
 import (
 	"context"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"terraform-provider-hashicups-pf/internal/api"
 	"terraform-provider-hashicups-pf/internal/utl"
@@ -45,3 +46,15 @@ func ConfigureDatasource(ctx context.Context, req *datasource.ConfigureRequest,
 	}
 	ds.SetAPIClient(client)
 }
+
+func ConfigureEphemeralResource(ctx context.Context, req *ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse, er Configurable) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*api.Client)
+	if !ok {
+		utl.EphemeralResourceConfigureError(req, resp)
+		return
+	}
+	er.SetAPIClient(client)
+}
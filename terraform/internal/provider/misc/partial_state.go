@@ -0,0 +1,37 @@
+package misc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// SetPartialState saves data into state with every unknown value replaced by null, so a
+// resource which exists remotely can be saved before all of its computed values are known.
+// Returning an error after it leaves the resource tainted in terraform
+func SetPartialState(ctx context.Context, state *tfsdk.State, data interface{}) diag.Diagnostics {
+	diags := state.Set(ctx, data)
+	if diags.HasError() {
+		return diags
+	}
+
+	raw, err := NullUnknownValues(state.Raw)
+	if err != nil {
+		diags.AddError("error saving partial state", err.Error())
+		return diags
+	}
+	state.Raw = raw
+	return diags
+}
+
+// NullUnknownValues replaces every unknown value nested in v with a null of the same type
+func NullUnknownValues(v tftypes.Value) (tftypes.Value, error) {
+	return tftypes.Transform(v, func(_ *tftypes.AttributePath, v tftypes.Value) (tftypes.Value, error) {
+		if !v.IsKnown() {
+			return tftypes.NewValue(v.Type(), nil), nil
+		}
+		return v, nil
+	})
+}
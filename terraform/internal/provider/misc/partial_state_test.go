@@ -0,0 +1,52 @@
+package misc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestNullUnknownValues(t *testing.T) {
+	netType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"network_id": tftypes.String,
+		"ip":         tftypes.String,
+	}}
+	typ := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"id":       tftypes.String,
+		"status":   tftypes.String,
+		"networks": tftypes.List{ElementType: netType},
+	}}
+
+	v := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "a"),
+		"status": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"networks": tftypes.NewValue(tftypes.List{ElementType: netType}, []tftypes.Value{
+			tftypes.NewValue(netType, map[string]tftypes.Value{
+				"network_id": tftypes.NewValue(tftypes.String, "n1"),
+				"ip":         tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+		}),
+	})
+
+	got, err := NullUnknownValues(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsFullyKnown() {
+		t.Fatalf("unknown values left in %s", got)
+	}
+
+	want := tftypes.NewValue(typ, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "a"),
+		"status": tftypes.NewValue(tftypes.String, nil),
+		"networks": tftypes.NewValue(tftypes.List{ElementType: netType}, []tftypes.Value{
+			tftypes.NewValue(netType, map[string]tftypes.Value{
+				"network_id": tftypes.NewValue(tftypes.String, "n1"),
+				"ip":         tftypes.NewValue(tftypes.String, nil),
+			}),
+		}),
+	})
+	if !got.Equal(want) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
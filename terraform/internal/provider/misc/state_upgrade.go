@@ -0,0 +1,103 @@
+package misc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// StateUpgradeFunc rewrites a decoded raw state in place
+type StateUpgradeFunc func(state map[string]interface{})
+
+// NewJSONStateUpgrader returns an upgrader working on the raw json state instead of a prior
+// schema, which lets old state files with attributes of a different shape be fixed up
+func NewJSONStateUpgrader(current schema.Schema, upgrade StateUpgradeFunc) resource.StateUpgrader {
+	return resource.StateUpgrader{
+		StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+			if req.RawState == nil {
+				resp.Diagnostics.AddError("error upgrading state", "no prior state to upgrade")
+				return
+			}
+
+			upgraded, err := UpgradeRawState(ctx, req.RawState.JSON, current.Type().TerraformType(ctx), upgrade)
+			if err != nil {
+				resp.Diagnostics.AddError("error upgrading state", err.Error())
+				return
+			}
+			resp.DynamicValue = &tfprotov6.DynamicValue{JSON: upgraded}
+		},
+	}
+}
+
+// UpgradeRawState applies upgrade on the raw json state, drops the attributes which are not
+// part of typ anymore and makes sure the result can be decoded as typ
+func UpgradeRawState(ctx context.Context, raw []byte, typ tftypes.Type, upgrade StateUpgradeFunc) ([]byte, error) {
+	var state map[string]interface{}
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode prior state: %w", err)
+	}
+	if state == nil {
+		return nil, fmt.Errorf("prior state is empty")
+	}
+
+	upgrade(state)
+
+	upgraded, err := json.Marshal(pruneToType(state, typ))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode upgraded state: %w", err)
+	}
+	if _, err := tftypes.ValueFromJSON(upgraded, typ); err != nil {
+		return nil, fmt.Errorf("upgraded state does not match the schema: %w", err)
+	}
+	return upgraded, nil
+}
+
+// pruneToType removes the object attributes not known by typ, missing ones are decoded as null
+func pruneToType(v interface{}, typ tftypes.Type) interface{} {
+	switch t := typ.(type) {
+	case tftypes.Object:
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		for k, item := range obj {
+			attrType, ok := t.AttributeTypes[k]
+			if !ok {
+				delete(obj, k)
+				continue
+			}
+			obj[k] = pruneToType(item, attrType)
+		}
+		return obj
+	case tftypes.List:
+		return pruneElements(v, t.ElementType)
+	case tftypes.Set:
+		return pruneElements(v, t.ElementType)
+	case tftypes.Map:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		for k, item := range m {
+			m[k] = pruneToType(item, t.ElementType)
+		}
+		return m
+	}
+	return v
+}
+
+func pruneElements(v interface{}, typ tftypes.Type) interface{} {
+	items, ok := v.([]interface{})
+	if !ok {
+		return v
+	}
+	for idx, item := range items {
+		items[idx] = pruneToType(item, typ)
+	}
+	return items
+}
@@ -37,8 +37,79 @@ var (
 			},
 		},
 	}
+
+	BackupV2S3Type = map[string]attr.Type{
+		"progress":    types.Int64Type,
+		"backup_id":   types.StringType,
+		"bucket":      types.StringType,
+		"region":      types.StringType,
+		"fail_reason": types.StringType,
+	}
 )
 
+type TFInstanceBackupModel struct {
+	Region       types.String `tfsdk:"region"`
+	ID           types.String `tfsdk:"id"`
+	InstanceID   types.String `tfsdk:"instance_id"`
+	Name         types.String `tfsdk:"name"`
+	Schedule     types.String `tfsdk:"schedule"`
+	Quota        types.Int64  `tfsdk:"quota"`
+	Labels       types.Set    `tfsdk:"labels"`
+	InstanceName types.String `tfsdk:"instance_name"`
+	Status       types.String `tfsdk:"status"`
+	NextBackup   types.String `tfsdk:"next_backup"`
+	Occupancy    types.Int64  `tfsdk:"occupancy"`
+	S3           types.Object `tfsdk:"s3"`
+}
+
+func (b *TFInstanceBackupModel) GetLabels(ctx context.Context) ([]string, diag.Diagnostics) {
+	var ret []string
+	d := b.Labels.ElementsAs(ctx, &ret, true)
+	return ret, d
+}
+
+func (b *TFInstanceBackupModel) SetLabels(ctx context.Context, labels []string) diag.Diagnostics {
+	l, d := types.SetValueFrom(ctx, types.StringType, labels)
+	if d.HasError() {
+		return d
+	}
+	b.Labels = l
+	return d
+}
+
+func (b *TFInstanceBackupModel) SetBackupData(ctx context.Context, backup *api.BackupListData) diag.Diagnostics {
+	b.Name = types.StringValue(backup.BackupName)
+	b.InstanceName = types.StringValue(backup.InstanceName)
+	b.Status = types.StringValue(backup.Status)
+	b.NextBackup = types.StringValue(backup.NextBackup)
+	b.Occupancy = types.Int64Value(int64(backup.Occupancy))
+	b.Quota = types.Int64Value(int64(backup.Quota))
+
+	if backup.Labels != nil {
+		d := b.SetLabels(ctx, backup.Labels)
+		if d.HasError() {
+			return d
+		}
+	}
+
+	if backup.S3 == nil {
+		b.S3 = types.ObjectNull(BackupV2S3Type)
+		return nil
+	}
+	obj, d := types.ObjectValue(BackupV2S3Type, map[string]attr.Value{
+		"progress":    types.Int64Value(int64(backup.S3.Progress)),
+		"backup_id":   types.StringValue(backup.S3.BackupID),
+		"bucket":      types.StringValue(backup.S3.Bucket),
+		"region":      types.StringValue(backup.S3.Region),
+		"fail_reason": types.StringValue(backup.S3.FailReason),
+	})
+	if d.HasError() {
+		return d
+	}
+	b.S3 = obj
+	return d
+}
+
 type TFBackupV2 struct {
 	Region  types.String `tfsdk:"region"`
 	Backups types.List   `tfsdk:"backups"`
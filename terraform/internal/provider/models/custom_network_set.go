@@ -70,45 +70,7 @@ func (v CustomNetworkSetValue) Equal(in attr.Value) bool {
 	if !ok {
 		return false
 	}
-	var otherNets []TFNetworkAttachment
-	d := other.ElementsAs(context.Background(), &otherNets, false)
-	if d.HasError() {
-		return false
-	}
-
-	var currentNets []TFNetworkAttachment
-	d = v.ElementsAs(context.Background(), &currentNets, false)
-	if d.HasError() {
-		return false
-	}
-
-	if len(currentNets) != len(otherNets) {
-		return false
-	}
-
-	otherNetSet := make(map[string]bool)
-	for _, x := range otherNets {
-		otherNetSet[x.NetworkID.ValueString()] = true
-	}
-
-	currentNetSet := make(map[string]bool)
-	for _, x := range currentNets {
-		currentNetSet[x.NetworkID.ValueString()] = true
-	}
-
-	for nid, _ := range currentNetSet {
-		if !otherNetSet[nid] {
-			return false
-		}
-	}
-
-	for nid, _ := range otherNetSet {
-		if !currentNetSet[nid] {
-			return false
-		}
-	}
-
-	return true
+	return v.SetValue.Equal(other.SetValue)
 }
 
 func (v CustomNetworkSetValue) Type(ctx context.Context) attr.Type {
@@ -132,12 +94,46 @@ func (v CustomNetworkSetValue) SetSemanticEquals(ctx context.Context, newVal bas
 		)
 		return false, diags
 	}
-	eq := v.Equal(other)
-	tflog.Warn(ctx, "NETWORK_EQUALITY_CHECK", map[string]interface{}{"EQUAL": eq})
+	var currentNets, otherNets []TFNetworkAttachment
+	diags.Append(v.ElementsAs(ctx, &currentNets, false)...)
+	diags.Append(other.ElementsAs(ctx, &otherNets, false)...)
+	if diags.HasError() {
+		return false, diags
+	}
+	eq := NetworkAttachmentsEqual(currentNets, otherNets)
+	tflog.Debug(ctx, "NETWORK_EQUALITY_CHECK", map[string]interface{}{"EQUAL": eq})
 	return eq, diags
 
 }
 
+// NetworkAttachmentSetType is the type of the networks attribute of an instance
+func NetworkAttachmentSetType() CustomNetworkSetType {
+	return CustomNetworkSetType{
+		SetType: basetypes.SetType{
+			ElemType: networkType,
+		},
+	}
+}
+
+// NetworkAttachmentsEqual compares attachments keyed by network id, so the order of
+// either slice does not matter
+func NetworkAttachmentsEqual(a, b []TFNetworkAttachment) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	byNetwork := make(map[string]TFNetworkAttachment)
+	for _, x := range a {
+		byNetwork[x.NetworkID.ValueString()] = x
+	}
+	for _, y := range b {
+		x, ok := byNetwork[y.NetworkID.ValueString()]
+		if !ok || !x.Equals(y) || !x.PTR.Equal(y.PTR) {
+			return false
+		}
+	}
+	return true
+}
+
 type CustomNetworkAttachmentType struct {
 	basetypes.ObjectType
 }
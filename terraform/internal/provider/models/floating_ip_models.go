@@ -29,6 +29,14 @@ type TFFloatingIPModel struct {
 	Description types.String `tfsdk:"description"`
 	Status      types.String `tfsdk:"status"`
 	Address     types.String `tfsdk:"address"`
+	PTR         types.String `tfsdk:"ptr"`
+}
+
+type TFPTRRecordModel struct {
+	Region types.String `tfsdk:"region"`
+	ID     types.String `tfsdk:"id"`
+	IP     types.String `tfsdk:"ip"`
+	Domain types.String `tfsdk:"domain"`
 }
 
 type TFFloatingIPDataSourceItem struct {
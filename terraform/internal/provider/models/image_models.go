@@ -1,6 +1,9 @@
 package models
 
-import "github.com/hashicorp/terraform-plugin-framework/types"
+import (
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
 
 type ImageItem struct {
 	ID         types.String `tfsdk:"id"`
@@ -17,3 +20,18 @@ type ImageDistroListModel struct {
 	Region        types.String `tfsdk:"region"`
 	Distributions []ImageItem  `tfsdk:"distributions"`
 }
+
+type TFPrivateImageModel struct {
+	Timeouts   timeouts.Value `tfsdk:"timeouts"`
+	Region     types.String   `tfsdk:"region"`
+	ID         types.String   `tfsdk:"id"`
+	Name       types.String   `tfsdk:"name"`
+	SourceURL  types.String   `tfsdk:"source_url"`
+	SourceFile types.String   `tfsdk:"source_file"`
+	DiskFormat types.String   `tfsdk:"disk_format"`
+	Checksum   types.String   `tfsdk:"checksum"`
+	Status     types.String   `tfsdk:"status"`
+	Progress   types.Int64    `tfsdk:"progress"`
+	Size       types.Int64    `tfsdk:"size"`
+	MinDisk    types.Int64    `tfsdk:"min_disk"`
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var (
+	instanceGroupMemberType = basetypes.ObjectType{
+		AttrTypes: map[string]attr.Type{
+			"index":  types.Int64Type,
+			"id":     types.StringType,
+			"name":   types.StringType,
+			"status": types.StringType,
+			"ips": types.MapType{
+				ElemType: types.StringType,
+			},
+		},
+	}
+)
+
+type TFInstanceGroupMember struct {
+	Index  types.Int64  `tfsdk:"index"`
+	ID     types.String `tfsdk:"id"`
+	Name   types.String `tfsdk:"name"`
+	Status types.String `tfsdk:"status"`
+	IPs    types.Map    `tfsdk:"ips"`
+}
+
+type TFInstanceGroupModel struct {
+	Timeouts       timeouts.Value `tfsdk:"timeouts"`
+	Region         types.String   `tfsdk:"region"`
+	ID             types.String   `tfsdk:"id"`
+	NameTemplate   types.String   `tfsdk:"name_template"`
+	InstanceCount  types.Int64    `tfsdk:"instance_count"`
+	ImageID        types.String   `tfsdk:"image_id"`
+	FlavorID       types.String   `tfsdk:"flavor_id"`
+	DiskSize       types.Int64    `tfsdk:"disk_size"`
+	Networks       types.List     `tfsdk:"networks"`
+	SecurityGroups types.Set      `tfsdk:"security_groups"`
+	SSHKeyName     types.String   `tfsdk:"ssh_key_name"`
+	InitScript     types.String   `tfsdk:"init_script"`
+	ServerGroupID  types.String   `tfsdk:"server_group_id"`
+	EnableIPv4     types.Bool     `tfsdk:"enable_ipv4"`
+	EnableIPv6     types.Bool     `tfsdk:"enable_ipv6"`
+	Members        types.List     `tfsdk:"members"`
+}
+
+func (g *TFInstanceGroupModel) GetNetworks(ctx context.Context) ([]string, diag.Diagnostics) {
+	var ret []string
+	d := g.Networks.ElementsAs(ctx, &ret, false)
+	return ret, d
+}
+
+func (g *TFInstanceGroupModel) GetSecurityGroups(ctx context.Context) ([]string, diag.Diagnostics) {
+	var ret []string
+	d := g.SecurityGroups.ElementsAs(ctx, &ret, false)
+	return ret, d
+}
+
+func (g *TFInstanceGroupModel) GetMembers(ctx context.Context) ([]TFInstanceGroupMember, diag.Diagnostics) {
+	var ret []TFInstanceGroupMember
+	if g.Members.IsNull() || g.Members.IsUnknown() {
+		return ret, nil
+	}
+	d := g.Members.ElementsAs(ctx, &ret, false)
+	return ret, d
+}
+
+func (g *TFInstanceGroupModel) SetMembers(ctx context.Context, members []TFInstanceGroupMember) diag.Diagnostics {
+	lv, diags := types.ListValueFrom(ctx, instanceGroupMemberType, members)
+	if diags.HasError() {
+		return diags
+	}
+	g.Members = lv
+	return nil
+}
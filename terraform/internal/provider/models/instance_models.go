@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"sort"
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -20,6 +21,10 @@ var (
 			"port_id":               types.StringType,
 			"is_public":             types.BoolType,
 			"port_security_enabled": types.BoolType,
+			"ptr":                   types.StringType,
+			"allowed_address_pairs": types.SetType{
+				ElemType: types.StringType,
+			},
 		},
 	}
 
@@ -68,23 +73,26 @@ type TFServerRevertTo struct {
 }
 
 type TFInstanceDetails struct {
-	ID             types.String                 `tfsdk:"id"`
-	Name           types.String                 `tfsdk:"name"`
-	Flavor         TFServerFlavor               `tfsdk:"flavor"`
-	Status         types.String                 `tfsdk:"status"`
-	Image          TFServerImage                `tfsdk:"image"`
-	Created        types.String                 `tfsdk:"created"`
-	Password       types.String                 `tfsdk:"password"`
-	TaskState      types.String                 `tfsdk:"task_state"`
-	KeyName        types.String                 `tfsdk:"key_name"`
-	SecurityGroups []TFSecurityGroup            `tfsdk:"security_groups"`
-	Addresses      map[string][]TFServerAddress `tfsdk:"addresses"`
-	Tags           []TFTag                      `tfsdk:"tags"`
-	HAEnabled      types.Bool                   `tfsdk:"ha_enabled"`
+	ID                types.String                 `tfsdk:"id"`
+	Name              types.String                 `tfsdk:"name"`
+	Flavor            TFServerFlavor               `tfsdk:"flavor"`
+	Status            types.String                 `tfsdk:"status"`
+	Image             TFServerImage                `tfsdk:"image"`
+	Created           types.String                 `tfsdk:"created"`
+	Password          types.String                 `tfsdk:"password"`
+	EncryptedPassword types.String                 `tfsdk:"encrypted_password"`
+	TaskState         types.String                 `tfsdk:"task_state"`
+	KeyName           types.String                 `tfsdk:"key_name"`
+	SecurityGroups    []TFSecurityGroup            `tfsdk:"security_groups"`
+	Addresses         map[string][]TFServerAddress `tfsdk:"addresses"`
+	Tags              []TFTag                      `tfsdk:"tags"`
+	HAEnabled         types.Bool                   `tfsdk:"ha_enabled"`
 }
 
 type TFInstanceDatasourceModel struct {
 	Region    types.String        `tfsdk:"region"`
+	Tags      []types.String      `tfsdk:"tags"`
+	PGPKey    types.String        `tfsdk:"pgp_key"`
 	Instances []TFInstanceDetails `tfsdk:"instances"`
 }
 
@@ -95,29 +103,51 @@ type TFServerVolume struct {
 }
 
 type TFInstanceResourceModel struct {
-	Timeouts          timeouts.Value `tfsdk:"timeouts"`
-	Region            types.String   `tfsdk:"region"`
-	ID                types.String   `tfsdk:"id"`
-	TaskID            types.String   `tfsdk:"task_id"`
-	Name              types.String   `tfsdk:"name"`
-	ImageID           types.String   `tfsdk:"image_id"`
-	Networks          types.List     `tfsdk:"networks"`
-	FlavorID          types.String   `tfsdk:"flavor_id"`
-	SecurityGroups    types.Set      `tfsdk:"security_groups"`
-	DiskSize          types.Int64    `tfsdk:"disk_size"`
-	InitScript        types.String   `tfsdk:"init_script"`
-	Volumes           types.Set      `tfsdk:"volumes"`
-	SSHKeyName        types.String   `tfsdk:"ssh_key_name"`
-	Password          types.String   `tfsdk:"password"`
-	Status            types.String   `tfsdk:"status"`
-	FloatingIP        types.Object   `tfsdk:"floating_ip"`
-	Snapshot          types.Object   `tfsdk:"revert_to"`
-	ServerGroupID     types.String   `tfsdk:"server_group_id"`
-	DedicatedServerID types.String   `tfsdk:"dedicated_server_id"`
-	ClusterID         types.String   `tfsdk:"cluster_id"`
-	SnapshotID        types.String   `tfsdk:"snapshot_id"`
-	EnableIPv4        types.Bool     `tfsdk:"enable_ipv4"`
-	EnableIPv6        types.Bool     `tfsdk:"enable_ipv6"`
+	Timeouts             timeouts.Value        `tfsdk:"timeouts"`
+	Region               types.String          `tfsdk:"region"`
+	ID                   types.String          `tfsdk:"id"`
+	TaskID               types.String          `tfsdk:"task_id"`
+	Name                 types.String          `tfsdk:"name"`
+	ImageID              types.String          `tfsdk:"image_id"`
+	Networks             CustomNetworkSetValue `tfsdk:"networks"`
+	FlavorID             types.String          `tfsdk:"flavor_id"`
+	SecurityGroups       types.Set             `tfsdk:"security_groups"`
+	DiskSize             types.Int64           `tfsdk:"disk_size"`
+	InitScript           types.String          `tfsdk:"init_script"`
+	Volumes              types.Set             `tfsdk:"volumes"`
+	SSHKeyName           types.String          `tfsdk:"ssh_key_name"`
+	SSHKeyNames          types.Set             `tfsdk:"ssh_key_names"`
+	Password             types.String          `tfsdk:"password"`
+	Status               types.String          `tfsdk:"status"`
+	FloatingIP           types.Object          `tfsdk:"floating_ip"`
+	Snapshot             types.Object          `tfsdk:"revert_to"`
+	ServerGroupID        types.String          `tfsdk:"server_group_id"`
+	DedicatedServerID    types.String          `tfsdk:"dedicated_server_id"`
+	MigrationMode        types.String          `tfsdk:"migration_mode"`
+	ClusterID            types.String          `tfsdk:"cluster_id"`
+	SnapshotID           types.String          `tfsdk:"snapshot_id"`
+	EnableIPv4           types.Bool            `tfsdk:"enable_ipv4"`
+	EnableIPv6           types.Bool            `tfsdk:"enable_ipv6"`
+	PowerState           types.String          `tfsdk:"power_state"`
+	RebootTriggers       types.Map             `tfsdk:"reboot_triggers"`
+	RebootType           types.String          `tfsdk:"reboot_type"`
+	Tags                 types.Set             `tfsdk:"tags"`
+	UserData             types.Object          `tfsdk:"user_data"`
+	RenderedUserData     types.String          `tfsdk:"rendered_user_data"`
+	Readiness            types.Object          `tfsdk:"readiness"`
+	BootVolumeID         types.String          `tfsdk:"boot_volume_id"`
+	HAEnabled            types.Bool            `tfsdk:"ha_enabled"`
+	RebuildOnImageChange types.Bool            `tfsdk:"rebuild_on_image_change"`
+	DeleteMode           types.String          `tfsdk:"delete_mode"`
+	DeletionProtection   types.Bool            `tfsdk:"deletion_protection"`
+	PGPKey               types.String          `tfsdk:"pgp_key"`
+	StorePassword        types.Bool            `tfsdk:"store_password"`
+	EncryptedPassword    types.String          `tfsdk:"encrypted_password"`
+	Rescue               types.Object          `tfsdk:"rescue"`
+	PublicIPv4           types.String          `tfsdk:"public_ipv4"`
+	PublicIPv6           types.String          `tfsdk:"public_ipv6"`
+	AccessIPv4           types.String          `tfsdk:"access_ip_v4"`
+	PrivateIPsByNetwork  types.Map             `tfsdk:"private_ips_by_network"`
 }
 
 func (i *TFInstanceResourceModel) GetSnapshot(ctx context.Context) (*TFServerRevertTo, diag.Diagnostics) {
@@ -166,29 +196,34 @@ func (i *TFInstanceResourceModel) SetFloatingIPAttachment(ctx context.Context, f
 	return d
 }
 
+// GetNetworkAttachments returns the attachments sorted by network id, networks is a set so
+// this is what "first network" means
 func (i *TFInstanceResourceModel) GetNetworkAttachments(ctx context.Context) ([]TFNetworkAttachment, diag.Diagnostics) {
 	var ret []TFNetworkAttachment
 	d := i.Networks.ElementsAs(ctx, &ret, false)
+	sort.SliceStable(ret, func(a, b int) bool {
+		return ret[a].NetworkID.ValueString() < ret[b].NetworkID.ValueString()
+	})
 	return ret, d
 }
 
 func (i *TFInstanceResourceModel) SetNetworkAttachments(ctx context.Context, attachments []TFNetworkAttachment) diag.Diagnostics {
-	lv, diags := types.ListValueFrom(ctx, networkType, attachments)
+	sv, diags := types.SetValueFrom(ctx, networkType, attachments)
 	if diags.HasError() {
 		return diags
 	}
-	i.Networks = lv
+	i.Networks = CustomNetworkSetValue{SetValue: sv}
 	return nil
 }
 
 func (i *TFInstanceResourceModel) SetNetworkAttachmentsIfNotEqual(ctx context.Context, attachments []TFNetworkAttachment) diag.Diagnostics {
-	lv, diags := types.ListValueFrom(ctx, networkType, attachments)
+	sv, diags := types.SetValueFrom(ctx, networkType, attachments)
 	if diags.HasError() {
 		return diags
 	}
 
-	if !i.Networks.Equal(lv) {
-		i.Networks = lv
+	if !i.Networks.Equal(CustomNetworkSetValue{SetValue: sv}) {
+		i.Networks = CustomNetworkSetValue{SetValue: sv}
 	}
 	return nil
 }
@@ -225,6 +260,27 @@ func (i *TFInstanceResourceModel) SetSecurityGroups(ctx context.Context, sgs map
 	return nil
 }
 
+func (i *TFInstanceResourceModel) GetTags(ctx context.Context) ([]string, diag.Diagnostics) {
+	var ret []string
+	if i.Tags.IsNull() || i.Tags.IsUnknown() {
+		return ret, nil
+	}
+	d := i.Tags.ElementsAs(ctx, &ret, false)
+	return ret, d
+}
+
+func (i *TFInstanceResourceModel) SetTags(ctx context.Context, tags []string) diag.Diagnostics {
+	if tags == nil {
+		tags = []string{}
+	}
+	sv, d := types.SetValueFrom(ctx, types.StringType, tags)
+	if d.HasError() {
+		return d
+	}
+	i.Tags = sv
+	return nil
+}
+
 func (i *TFInstanceResourceModel) GetSecurityGroups(ctx context.Context) ([]string, diag.Diagnostics) {
 	var ret []string
 	d := i.SecurityGroups.ElementsAs(ctx, &ret, true)
@@ -267,13 +323,49 @@ type TFNetworkAttachment struct {
 	PortID              types.String `tfsdk:"port_id"`
 	IsPublic            types.Bool   `tfsdk:"is_public"`
 	PortSecurityEnabled types.Bool   `tfsdk:"port_security_enabled"`
+	PTR                 types.String `tfsdk:"ptr"`
+	AllowedAddressPairs types.Set    `tfsdk:"allowed_address_pairs"`
 }
 
 func (a *TFNetworkAttachment) Equals(t TFNetworkAttachment) bool {
-	return *a == t
+	return a.EqualsIgnoringAddressPairs(t) && a.AllowedAddressPairs.Equal(t.AllowedAddressPairs)
+}
+
+// EqualsIgnoringAddressPairs reports whether both attachments refer to the same port
+// configuration, allowed address pairs can be changed without reattaching the port
+func (a *TFNetworkAttachment) EqualsIgnoringAddressPairs(t TFNetworkAttachment) bool {
+	return a.IP.Equal(t.IP) &&
+		a.SubnetID.Equal(t.SubnetID) &&
+		a.NetworkID.Equal(t.NetworkID) &&
+		a.PortID.Equal(t.PortID) &&
+		a.IsPublic.Equal(t.IsPublic) &&
+		a.PortSecurityEnabled.Equal(t.PortSecurityEnabled)
+}
+
+func (a *TFNetworkAttachment) GetAllowedAddressPairs(ctx context.Context) ([]string, diag.Diagnostics) {
+	var ret []string
+	if a.AllowedAddressPairs.IsNull() || a.AllowedAddressPairs.IsUnknown() {
+		return ret, nil
+	}
+	d := a.AllowedAddressPairs.ElementsAs(ctx, &ret, false)
+	return ret, d
 }
 
 type TFFloatingIPAttachment struct {
 	FloatingID types.String `tfsdk:"floating_ip_id"`
 	NetworkID  types.String `tfsdk:"network_id"`
 }
+
+type TFInstancePasswordModel struct {
+	Region   types.String `tfsdk:"region"`
+	ID       types.String `tfsdk:"id"`
+	Password types.String `tfsdk:"password"`
+}
+
+type TFInstanceConsoleModel struct {
+	Region   types.String `tfsdk:"region"`
+	ID       types.String `tfsdk:"id"`
+	LogLines types.Int64  `tfsdk:"log_lines"`
+	URL      types.String `tfsdk:"url"`
+	Log      types.String `tfsdk:"log"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+type TFReadinessTCP struct {
+	Port      types.Int64  `tfsdk:"port"`
+	NetworkID types.String `tfsdk:"network_id"`
+	Timeout   types.String `tfsdk:"timeout"`
+}
+
+type TFReadinessHTTP struct {
+	Scheme    types.String `tfsdk:"scheme"`
+	Port      types.Int64  `tfsdk:"port"`
+	Path      types.String `tfsdk:"path"`
+	NetworkID types.String `tfsdk:"network_id"`
+	Timeout   types.String `tfsdk:"timeout"`
+}
+
+type TFReadinessConsole struct {
+	Marker  types.String `tfsdk:"marker"`
+	Timeout types.String `tfsdk:"timeout"`
+}
+
+type TFReadiness struct {
+	TCP     *TFReadinessTCP     `tfsdk:"tcp"`
+	HTTP    *TFReadinessHTTP    `tfsdk:"http"`
+	Console *TFReadinessConsole `tfsdk:"console"`
+}
+
+func (i *TFInstanceResourceModel) GetReadiness(ctx context.Context) (*TFReadiness, diag.Diagnostics) {
+	var ret TFReadiness
+	var d diag.Diagnostics
+	if i.Readiness.IsNull() || i.Readiness.IsUnknown() {
+		return nil, d
+	}
+	d = i.Readiness.As(ctx, &ret, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if d.HasError() {
+		return nil, d
+	}
+	return &ret, d
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+)
+
+var rescueObjType = basetypes.ObjectType{
+	AttrTypes: map[string]attr.Type{
+		"enabled":  types.BoolType,
+		"image_id": types.StringType,
+	},
+}
+
+type TFRescue struct {
+	Enabled types.Bool   `tfsdk:"enabled"`
+	ImageID types.String `tfsdk:"image_id"`
+}
+
+func (i *TFInstanceResourceModel) GetRescue(ctx context.Context) (*TFRescue, diag.Diagnostics) {
+	var ret TFRescue
+	var d diag.Diagnostics
+	if i.Rescue.IsNull() || i.Rescue.IsUnknown() {
+		return nil, d
+	}
+	d = i.Rescue.As(ctx, &ret, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if d.HasError() {
+		return nil, d
+	}
+	return &ret, d
+}
+
+func (i *TFInstanceResourceModel) SetRescue(ctx context.Context, rescue *TFRescue) diag.Diagnostics {
+	var d diag.Diagnostics
+	if rescue == nil {
+		i.Rescue = types.ObjectNull(rescueObjType.AttrTypes)
+		return d
+	}
+	i.Rescue, d = types.ObjectValueFrom(ctx, rescueObjType.AttrTypes, rescue)
+	return d
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"terraform-provider-hashicups-pf/internal/utl"
+)
+
+type TFUserDataUser struct {
+	Name              types.String `tfsdk:"name"`
+	Groups            types.List   `tfsdk:"groups"`
+	Shell             types.String `tfsdk:"shell"`
+	Sudo              types.String `tfsdk:"sudo"`
+	SSHAuthorizedKeys types.List   `tfsdk:"ssh_authorized_keys"`
+}
+
+type TFUserDataFile struct {
+	Path        types.String `tfsdk:"path"`
+	Content     types.String `tfsdk:"content"`
+	Permissions types.String `tfsdk:"permissions"`
+	Owner       types.String `tfsdk:"owner"`
+}
+
+type TFUserData struct {
+	Users             []TFUserDataUser `tfsdk:"users"`
+	SSHAuthorizedKeys types.List       `tfsdk:"ssh_authorized_keys"`
+	Packages          types.List       `tfsdk:"packages"`
+	WriteFiles        []TFUserDataFile `tfsdk:"write_files"`
+	RunCmd            types.List       `tfsdk:"runcmd"`
+	BootCmd           types.List       `tfsdk:"bootcmd"`
+}
+
+func (u *TFUserData) ToCloudConfig(ctx context.Context) (*utl.CloudConfig, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	listValue := func(l types.List) []string {
+		var ret []string
+		if l.IsNull() || l.IsUnknown() {
+			return ret
+		}
+		diags.Append(l.ElementsAs(ctx, &ret, false)...)
+		return ret
+	}
+
+	cfg := &utl.CloudConfig{
+		SSHAuthorizedKeys: listValue(u.SSHAuthorizedKeys),
+		Packages:          listValue(u.Packages),
+		RunCmd:            listValue(u.RunCmd),
+		BootCmd:           listValue(u.BootCmd),
+	}
+	for _, user := range u.Users {
+		cfg.Users = append(cfg.Users, utl.CloudConfigUser{
+			Name:              user.Name.ValueString(),
+			Groups:            listValue(user.Groups),
+			Shell:             user.Shell.ValueString(),
+			Sudo:              user.Sudo.ValueString(),
+			SSHAuthorizedKeys: listValue(user.SSHAuthorizedKeys),
+		})
+	}
+	for _, f := range u.WriteFiles {
+		cfg.WriteFiles = append(cfg.WriteFiles, utl.CloudConfigFile{
+			Path:        f.Path.ValueString(),
+			Content:     f.Content.ValueString(),
+			Permissions: f.Permissions.ValueString(),
+			Owner:       f.Owner.ValueString(),
+		})
+	}
+	return cfg, diags
+}
+
+// UserDataFromObject converts the user_data attribute, returns nil if it is not set
+func UserDataFromObject(ctx context.Context, obj types.Object) (*TFUserData, diag.Diagnostics) {
+	var ret TFUserData
+	var d diag.Diagnostics
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, d
+	}
+	d = obj.As(ctx, &ret, basetypes.ObjectAsOptions{
+		UnhandledNullAsEmpty:    true,
+		UnhandledUnknownAsEmpty: true,
+	})
+	if d.HasError() {
+		return nil, d
+	}
+	return &ret, d
+}
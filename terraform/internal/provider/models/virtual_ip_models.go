@@ -0,0 +1,14 @@
+package models
+
+import "github.com/hashicorp/terraform-plugin-framework/types"
+
+type TFVirtualIPModel struct {
+	Region     types.String `tfsdk:"region"`
+	ID         types.String `tfsdk:"id"`
+	Name       types.String `tfsdk:"name"`
+	NetworkID  types.String `tfsdk:"network_id"`
+	SubnetID   types.String `tfsdk:"subnet_id"`
+	IP         types.String `tfsdk:"ip"`
+	MacAddress types.String `tfsdk:"mac_address"`
+	Status     types.String `tfsdk:"status"`
+}
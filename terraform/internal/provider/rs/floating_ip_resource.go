@@ -58,6 +58,12 @@ func (f *FloatingIPResource) Schema(ctx context.Context, req resource.SchemaRequ
 					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
+			"ptr": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -77,6 +83,7 @@ func (f *FloatingIPResource) Create(ctx context.Context, req resource.CreateRequ
 	planData.ID = types.StringValue(apiResp.ID)
 	planData.Address = types.StringValue(apiResp.FloatingIPAddress)
 	planData.Status = types.StringValue(apiResp.Status)
+	planData.PTR = types.StringValue("")
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
 }
@@ -100,6 +107,18 @@ func (f *FloatingIPResource) Read(ctx context.Context, req resource.ReadRequest,
 	utl.AssignStringIfChanged(&data.Status, apiResp.Status)
 	utl.AssignStringIfChanged(&data.Address, apiResp.FloatingIPAddress)
 
+	// ptr is informational, a failing ptr lookup keeps the previous value instead of failing refresh
+	ptr, err := f.client.PTR.GetPTR(ctx, data.Region.ValueString(), apiResp.FloatingIPAddress)
+	if err != nil {
+		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+			data.PTR = types.StringValue("")
+		} else {
+			resp.Diagnostics.AddWarning("error fetching ptr record", err.Error())
+		}
+	} else {
+		data.PTR = types.StringValue(ptr.Domain)
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
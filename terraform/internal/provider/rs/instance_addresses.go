@@ -0,0 +1,240 @@
+package rs
+
+import (
+	"context"
+	"net"
+	"sort"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// addressInputs are the attributes whose change can move the addresses of an instance
+var addressInputs = []string{"networks", "enable_ipv4", "enable_ipv6", "floating_ip"}
+
+func addressAttributes() map[string]schema.Attribute {
+	str := func() schema.StringAttribute {
+		return schema.StringAttribute{
+			Computed: true,
+			PlanModifiers: []planmodifier.String{
+				addressViewModifier{},
+			},
+		}
+	}
+
+	return map[string]schema.Attribute{
+		"public_ipv4":  str(),
+		"public_ipv6":  str(),
+		"access_ip_v4": str(),
+		"private_ips_by_network": schema.MapAttribute{
+			Computed:    true,
+			ElementType: types.StringType,
+			PlanModifiers: []planmodifier.Map{
+				addressViewModifier{},
+			},
+		},
+	}
+}
+
+type instanceAddressView struct {
+	PublicIPv4 string
+	PublicIPv6 string
+	AccessIPv4 string
+	PrivateIPs map[string]string
+}
+
+// addressIsV6 uses the version reported by the api and falls back to parsing the address
+func addressIsV6(a *api.ServerAddress) bool {
+	switch a.Version {
+	case "4":
+		return false
+	case "6":
+		return true
+	}
+	ip := net.ParseIP(a.Addr)
+	return ip != nil && ip.To4() == nil
+}
+
+// newAddressView collects the public addresses of the instance from addresses and the
+// private ones from attachments. access_ip_v4 prefers a floating ip, then the public ipv4,
+// then the private ip of the first network in networkOrder
+func newAddressView(addresses map[string][]*api.ServerAddress, attachments map[string]api.NetworkAttachment, networkOrder []string) instanceAddressView {
+	view := instanceAddressView{PrivateIPs: make(map[string]string)}
+
+	// addresses is keyed by network name, sort it so the first address found is stable
+	names := make([]string, 0, len(addresses))
+	for name := range addresses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var floatingIPv4 string
+	for _, name := range names {
+		for _, a := range addresses[name] {
+			if a == nil || a.Addr == "" {
+				continue
+			}
+			switch {
+			case a.Type == "floating" && !addressIsV6(a):
+				if floatingIPv4 == "" {
+					floatingIPv4 = a.Addr
+				}
+			case !a.IsPublic:
+			case addressIsV6(a):
+				if view.PublicIPv6 == "" {
+					view.PublicIPv6 = a.Addr
+				}
+			default:
+				if view.PublicIPv4 == "" {
+					view.PublicIPv4 = a.Addr
+				}
+			}
+		}
+	}
+
+	for id, a := range attachments {
+		if !a.IsPublic && a.IP != "" {
+			view.PrivateIPs[id] = a.IP
+		}
+	}
+
+	switch {
+	case floatingIPv4 != "":
+		view.AccessIPv4 = floatingIPv4
+	case view.PublicIPv4 != "":
+		view.AccessIPv4 = view.PublicIPv4
+	default:
+		for _, id := range networkOrder {
+			if ip, ok := view.PrivateIPs[id]; ok && net.ParseIP(ip).To4() != nil {
+				view.AccessIPv4 = ip
+				break
+			}
+		}
+	}
+	return view
+}
+
+func setAddressView(ctx context.Context, data *models.TFInstanceResourceModel, view instanceAddressView) diag.Diagnostics {
+	optional := func(s string) types.String {
+		if s == "" {
+			return types.StringNull()
+		}
+		return types.StringValue(s)
+	}
+	data.PublicIPv4 = optional(view.PublicIPv4)
+	data.PublicIPv6 = optional(view.PublicIPv6)
+	data.AccessIPv4 = optional(view.AccessIPv4)
+
+	var d diag.Diagnostics
+	data.PrivateIPsByNetwork, d = types.MapValueFrom(ctx, types.StringType, view.PrivateIPs)
+	return d
+}
+
+// refreshAddressView fetches the instance and its network attachments to fill the address
+// attributes
+func (i *InstanceResource) refreshAddressView(ctx context.Context, data *models.TFInstanceResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	detail, err := i.client.Instance.GetInstance(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		diags.AddError("error fetching instance addresses", err.Error())
+		return diags
+	}
+	attachments, err := i.client.GetNetworkAttachments(ctx, detail, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		diags.AddError("error getting network attachments", err.Error())
+		return diags
+	}
+
+	nets, d := data.GetNetworkAttachments(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+	diags.Append(setAddressView(ctx, data, newAddressView(detail.Addresses, attachments, networkIDs(nets)))...)
+	return diags
+}
+
+// addressesChanged reports whether the update can move the addresses of the instance
+func addressesChanged(stateData, planData *models.TFInstanceResourceModel) bool {
+	return !planData.Networks.Equal(stateData.Networks) ||
+		!planData.EnableIPv4.Equal(stateData.EnableIPv4) ||
+		!planData.EnableIPv6.Equal(stateData.EnableIPv6) ||
+		!planData.FloatingIP.Equal(stateData.FloatingIP)
+}
+
+func networkIDs(nets []models.TFNetworkAttachment) []string {
+	var ret []string
+	for _, n := range nets {
+		ret = append(ret, n.NetworkID.ValueString())
+	}
+	return ret
+}
+
+// addressViewModifier keeps the address attributes from state unless an attribute that can
+// move the addresses changes
+type addressViewModifier struct{}
+
+func (m addressViewModifier) Description(context.Context) string {
+	return ""
+}
+
+func (m addressViewModifier) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+// keepState reports whether none of the address inputs change between state and plan
+func (m addressViewModifier) keepState(state tfsdk.State, plan tfsdk.Plan) (bool, error) {
+	if state.Raw.IsNull() || plan.Raw.IsNull() {
+		return false, nil
+	}
+	for _, name := range addressInputs {
+		attrPath := tftypes.NewAttributePath().WithAttributeName(name)
+		planValue, _, err := tftypes.WalkAttributePath(plan.Raw, attrPath)
+		if err != nil {
+			return false, err
+		}
+		stateValue, _, err := tftypes.WalkAttributePath(state.Raw, attrPath)
+		if err != nil {
+			return false, err
+		}
+		if !planValue.(tftypes.Value).Equal(stateValue.(tftypes.Value)) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (m addressViewModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if !req.PlanValue.IsUnknown() {
+		return
+	}
+	keep, err := m.keepState(req.State, req.Plan)
+	if err != nil {
+		resp.Diagnostics.AddError("error planning addresses", err.Error())
+		return
+	}
+	if keep {
+		resp.PlanValue = req.StateValue
+	}
+}
+
+func (m addressViewModifier) PlanModifyMap(ctx context.Context, req planmodifier.MapRequest, resp *planmodifier.MapResponse) {
+	if !req.PlanValue.IsUnknown() {
+		return
+	}
+	keep, err := m.keepState(req.State, req.Plan)
+	if err != nil {
+		resp.Diagnostics.AddError("error planning addresses", err.Error())
+		return
+	}
+	if keep {
+		resp.PlanValue = req.StateValue
+	}
+}
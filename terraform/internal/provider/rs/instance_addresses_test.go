@@ -0,0 +1,74 @@
+package rs
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-hashicups-pf/internal/api"
+)
+
+func TestNewAddressView(t *testing.T) {
+	attachments := map[string]api.NetworkAttachment{
+		"net-a":  {NetworkID: "net-a", IP: "10.0.0.5"},
+		"net-b":  {NetworkID: "net-b", IP: "192.168.1.7"},
+		"public": {NetworkID: "public", IP: "185.1.2.3", IsPublic: true},
+	}
+
+	tests := []struct {
+		name      string
+		addresses map[string][]*api.ServerAddress
+		order     []string
+		expected  instanceAddressView
+	}{
+		{
+			name: "public v4 and v6",
+			addresses: map[string][]*api.ServerAddress{
+				"public210": {
+					{Addr: "185.1.2.3", Version: "4", IsPublic: true},
+					{Addr: "2a01:e5c0::10", IsPublic: true},
+				},
+				"private-a": {{Addr: "10.0.0.5", Version: "4"}},
+			},
+			order: []string{"net-b", "net-a"},
+			expected: instanceAddressView{
+				PublicIPv4: "185.1.2.3",
+				PublicIPv6: "2a01:e5c0::10",
+				AccessIPv4: "185.1.2.3",
+				PrivateIPs: map[string]string{"net-a": "10.0.0.5", "net-b": "192.168.1.7"},
+			},
+		},
+		{
+			name: "floating ip wins",
+			addresses: map[string][]*api.ServerAddress{
+				"public210": {{Addr: "185.1.2.3", Version: "4", IsPublic: true}},
+				"private-a": {
+					{Addr: "10.0.0.5", Version: "4"},
+					{Addr: "185.9.9.9", Version: "4", Type: "floating"},
+				},
+			},
+			order: []string{"net-a"},
+			expected: instanceAddressView{
+				PublicIPv4: "185.1.2.3",
+				AccessIPv4: "185.9.9.9",
+				PrivateIPs: map[string]string{"net-a": "10.0.0.5", "net-b": "192.168.1.7"},
+			},
+		},
+		{
+			name:      "private only",
+			addresses: map[string][]*api.ServerAddress{"private-a": {{Addr: "10.0.0.5", Version: "4"}}},
+			order:     []string{"net-b", "net-a"},
+			expected: instanceAddressView{
+				AccessIPv4: "192.168.1.7",
+				PrivateIPs: map[string]string{"net-a": "10.0.0.5", "net-b": "192.168.1.7"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newAddressView(tt.addresses, attachments, tt.order)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
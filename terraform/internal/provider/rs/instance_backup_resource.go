@@ -0,0 +1,275 @@
+package rs
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+// InstanceBackupResource enables scheduled (v2) backups for an instance, destroying
+// the resource disables them again
+type InstanceBackupResource struct {
+	client *api.Client
+}
+
+func (b *InstanceBackupResource) SetAPIClient(c *api.Client) {
+	b.client = c
+}
+
+func (b *InstanceBackupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_backup"
+}
+
+func (b *InstanceBackupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	misc.ConfigureResource(ctx, &req, resp, b)
+}
+
+func (b *InstanceBackupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"instance_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"schedule": schema.StringAttribute{
+				Required:    true,
+				Description: "the api does not return the schedule, so changes made outside of terraform are not detected",
+			},
+			"quota": schema.Int64Attribute{
+				Required: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"labels": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+			},
+			"instance_name": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+			"next_backup": schema.StringAttribute{
+				Computed: true,
+			},
+			"occupancy": schema.Int64Attribute{
+				Computed: true,
+			},
+			"s3": schema.SingleNestedAttribute{
+				Computed: true,
+				Attributes: map[string]schema.Attribute{
+					"progress": schema.Int64Attribute{
+						Computed: true,
+					},
+					"backup_id": schema.StringAttribute{
+						Computed: true,
+					},
+					"bucket": schema.StringAttribute{
+						Computed: true,
+					},
+					"region": schema.StringAttribute{
+						Computed: true,
+					},
+					"fail_reason": schema.StringAttribute{
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func (b *InstanceBackupResource) backupRequest(ctx context.Context, data *models.TFInstanceBackupModel) (*api.InstanceBackupRequest, diag.Diagnostics) {
+	labels, d := data.GetLabels(ctx)
+	if d.HasError() {
+		return nil, d
+	}
+	if labels == nil {
+		labels = []string{}
+	}
+	return &api.InstanceBackupRequest{
+		BackupName: data.Name.ValueString(),
+		Schedule:   data.Schedule.ValueString(),
+		Quota:      int(data.Quota.ValueInt64()),
+		Labels:     labels,
+	}, d
+}
+
+// disableBackup turns the backup of the instance off, an already disabled backup is not an error
+func (b *InstanceBackupResource) disableBackup(ctx context.Context, region, instanceID string) error {
+	_, err := b.client.BackupV2.DeleteBackups(ctx, region, &api.DeleteInstanceBackupsRequest{
+		InstanceIDs: []string{instanceID},
+	})
+	if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+		return nil
+	}
+	return err
+}
+
+// waitForBackup waits until the backup of the instance shows up in the list, since
+// enabling it is not synchronous
+func (b *InstanceBackupResource) waitForBackup(ctx context.Context, region, instanceID string) (*api.BackupListData, error) {
+	var backup *api.BackupListData
+	err := b.client.WaitForCondition(ctx, time.Minute*2, func() (bool, error) {
+		var err error
+		backup, err = b.client.BackupV2.GetInstanceBackup(ctx, region, instanceID)
+		if err != nil {
+			if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return backup, nil
+}
+
+func (b *InstanceBackupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TFInstanceBackupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backupReq, d := b.backupRequest(ctx, &data)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	backupReq.InstanceID = data.InstanceID.ValueString()
+
+	_, err := b.client.BackupV2.CreateBackup(ctx, data.Region.ValueString(), backupReq)
+	if err != nil {
+		resp.Diagnostics.AddError("error enabling backup", err.Error())
+		return
+	}
+	data.ID = data.InstanceID
+
+	backup, err := b.waitForBackup(ctx, data.Region.ValueString(), data.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching backup", err.Error())
+	} else {
+		resp.Diagnostics.Append(data.SetBackupData(ctx, backup)...)
+	}
+	if resp.Diagnostics.HasError() {
+		// nothing is saved to state, so the backup is disabled again to let the next apply retry
+		if err := b.disableBackup(ctx, data.Region.ValueString(), data.InstanceID.ValueString()); err != nil {
+			resp.Diagnostics.AddError("error disabling backup", err.Error())
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (b *InstanceBackupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.TFInstanceBackupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backup, err := b.client.BackupV2.GetInstanceBackup(ctx, data.Region.ValueString(), data.InstanceID.ValueString())
+	if err != nil {
+		if misc.RemoveResourceIfNotFound(ctx, resp, err) {
+			return
+		}
+		resp.Diagnostics.AddError("error fetching backup", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(data.SetBackupData(ctx, backup)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (b *InstanceBackupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var planData models.TFInstanceBackupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	backupReq, d := b.backupRequest(ctx, &planData)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := b.client.BackupV2.UpdateBackup(ctx, planData.Region.ValueString(), planData.InstanceID.ValueString(), backupReq)
+	if err != nil {
+		resp.Diagnostics.AddError("error updating backup", err.Error())
+		return
+	}
+
+	backup, err := b.client.BackupV2.GetInstanceBackup(ctx, planData.Region.ValueString(), planData.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching backup", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(planData.SetBackupData(ctx, backup)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
+}
+
+func (b *InstanceBackupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TFInstanceBackupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := b.disableBackup(ctx, data.Region.ValueString(), data.InstanceID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error disabling backup", err.Error())
+	}
+}
+
+func NewInstanceBackupResource() resource.Resource {
+	return &InstanceBackupResource{}
+}
@@ -0,0 +1,578 @@
+package rs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// InstanceGroupResource manages a number of identical instances, members are created
+// with a single api call and named after name_template with their index
+type InstanceGroupResource struct {
+	client *api.Client
+}
+
+func (g *InstanceGroupResource) SetAPIClient(c *api.Client) {
+	g.client = c
+}
+
+func (g *InstanceGroupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_abrak_group"
+}
+
+func (g *InstanceGroupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	misc.ConfigureResource(ctx, &req, resp, g)
+}
+
+func (g *InstanceGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_template": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					utl.NameTemplateValidator(),
+				},
+			},
+			"instance_count": schema.Int64Attribute{
+				Required: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"image_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"flavor_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"disk_size": schema.Int64Attribute{
+				Required: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(25),
+				},
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			"networks": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
+				},
+			},
+			"security_groups": schema.SetAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			"ssh_key_name": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"init_script": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"server_group_id": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"enable_ipv4": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"enable_ipv6": schema.BoolAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"members": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"index": schema.Int64Attribute{
+							Computed: true,
+						},
+						"id": schema.StringAttribute{
+							Computed: true,
+						},
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+						"ips": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (g *InstanceGroupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, d := data.Timeouts.Create(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id, err := randomGroupToken()
+	if err != nil {
+		resp.Diagnostics.AddError("error generating group id", err.Error())
+		return
+	}
+	data.ID = types.StringValue(id)
+
+	members, err := g.createMembers(ctx, &data, missingGroupIndices(nil, data.InstanceCount.ValueInt64()), createTimeout)
+	resp.Diagnostics.Append(data.SetMembers(ctx, members)...)
+	if err != nil {
+		resp.Diagnostics.AddError("error creating instance group", err.Error())
+	}
+	if len(members) == 0 && resp.Diagnostics.HasError() {
+		return
+	}
+
+	// members created so far are saved even on failure, so they get cleaned up later
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (g *InstanceGroupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, d := data.GetMembers(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	networkIDs, d := data.GetNetworks(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var existing []models.TFInstanceGroupMember
+	for _, m := range members {
+		detail, err := g.client.Instance.GetInstance(ctx, data.Region.ValueString(), m.ID.ValueString())
+		if err != nil {
+			if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+				tflog.Warn(ctx, "instance group member not found", map[string]interface{}{"id": m.ID.ValueString()})
+				continue
+			}
+			resp.Diagnostics.AddError("error fetching instance group member", err.Error())
+			return
+		}
+		m.Name = types.StringValue(detail.Name)
+		m.Status = types.StringValue(detail.Status)
+
+		m.IPs, d = g.memberIPs(ctx, data.Region.ValueString(), networkIDs, m.ID.ValueString())
+		resp.Diagnostics.Append(d...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		existing = append(existing, m)
+	}
+
+	if len(existing) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(data.SetMembers(ctx, existing)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// ModifyPlan plans an update when members were deleted outside of terraform, Update then
+// creates the missing indices again
+func (g *InstanceGroupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var state models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	var count types.Int64
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("instance_count"), &count)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, d := state.GetMembers(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() || !groupMembersMissing(members, count) {
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("members"), types.ListUnknown(state.Members.ElementType(ctx)))...)
+}
+
+func (g *InstanceGroupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var planData models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var stateData models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, d := stateData.GetMembers(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	planData.ID = stateData.ID
+
+	// scale down by removing the members with the highest indices
+	var kept, removed []models.TFInstanceGroupMember
+	for _, m := range members {
+		if m.Index.ValueInt64() > planData.InstanceCount.ValueInt64() {
+			removed = append(removed, m)
+		} else {
+			kept = append(kept, m)
+		}
+	}
+	if len(removed) > 0 {
+		err := g.deleteMembers(ctx, planData.Region.ValueString(), removed, updateTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError("error deleting instance group members", err.Error())
+			return
+		}
+	}
+
+	for idx := range kept {
+		name := groupMemberName(planData.NameTemplate.ValueString(), kept[idx].Index.ValueInt64())
+		if kept[idx].Name.ValueString() == name {
+			continue
+		}
+		err := g.client.Instance.RenameInstance(ctx, planData.Region.ValueString(), kept[idx].ID.ValueString(), name)
+		if err != nil {
+			resp.Diagnostics.AddError("error renaming instance group member", err.Error())
+			return
+		}
+		kept[idx].Name = types.StringValue(name)
+	}
+
+	missing := missingGroupIndices(kept, planData.InstanceCount.ValueInt64())
+	if len(missing) > 0 {
+		created, err := g.createMembers(ctx, &planData, missing, updateTimeout)
+		kept = append(kept, created...)
+		if err != nil {
+			resp.Diagnostics.AddError("error creating instance group members", err.Error())
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool {
+		return kept[i].Index.ValueInt64() < kept[j].Index.ValueInt64()
+	})
+	resp.Diagnostics.Append(planData.SetMembers(ctx, kept)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
+}
+
+func (g *InstanceGroupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TFInstanceGroupModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deleteTimeout, d := data.Timeouts.Delete(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	members, d := data.GetMembers(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := g.deleteMembers(ctx, data.Region.ValueString(), members, deleteTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError("error deleting instance group", err.Error())
+	}
+}
+
+// createMembers creates one instance per index in a single request. The servers are
+// created under a unique temporary name, found by it and renamed after the template
+func (g *InstanceGroupResource) createMembers(ctx context.Context, data *models.TFInstanceGroupModel, indices []int64, timeout time.Duration) ([]models.TFInstanceGroupMember, error) {
+	region := data.Region.ValueString()
+
+	token, err := randomGroupToken()
+	if err != nil {
+		return nil, err
+	}
+	token = fmt.Sprintf("tf-%s-%s", data.ID.ValueString(), token)
+
+	networkIDs, d := data.GetNetworks(ctx)
+	if d.HasError() {
+		return nil, fmt.Errorf("failed to read networks")
+	}
+	sgs, d := data.GetSecurityGroups(ctx)
+	if d.HasError() {
+		return nil, fmt.Errorf("failed to read security groups")
+	}
+
+	apiCreateReq := api.InstanceCreateRequest{
+		Name:          token,
+		Count:         len(indices),
+		ImageID:       data.ImageID.ValueString(),
+		FlavorID:      data.FlavorID.ValueString(),
+		NetworkIDs:    networkIDs,
+		SSHKey:        !data.SSHKeyName.IsNull(),
+		DiskSize:      int(data.DiskSize.ValueInt64()),
+		InitScript:    data.InitScript.ValueString(),
+		ServerGroupID: data.ServerGroupID.ValueString(),
+		EnableIPv4:    data.EnableIPv4.ValueBool(),
+		EnableIPv6:    data.EnableIPv6.ValueBool(),
+	}
+	if apiCreateReq.SSHKey {
+		apiCreateReq.KeyName = data.SSHKeyName.ValueString()
+	}
+	for _, sg := range sgs {
+		apiCreateReq.SecurityGroups = append(apiCreateReq.SecurityGroups, api.SecGroupName{
+			Name: sg,
+		})
+	}
+
+	_, err = g.client.Instance.CreateInstanceAsync(ctx, region, &apiCreateReq)
+	if err != nil {
+		return nil, err
+	}
+
+	var servers []api.ServerDetail
+	var createErr error
+	err = g.client.WaitForCondition(ctx, timeout, func() (bool, error) {
+		list, err := g.client.Instance.ListInstances(ctx, region)
+		if err != nil {
+			return false, err
+		}
+
+		servers = nil
+		active := 0
+		for _, s := range list {
+			if !strings.HasPrefix(s.Name, token) {
+				continue
+			}
+			servers = append(servers, s)
+			switch s.Status {
+			case "ACTIVE":
+				active++
+			case "ERROR":
+				createErr = fmt.Errorf("instance %s transitioned into invalid state ERROR", s.ID)
+				return true, nil
+			}
+		}
+		return active == len(indices), nil
+	})
+	if err == nil {
+		err = createErr
+	}
+
+	sort.Slice(servers, func(i, j int) bool {
+		if servers[i].Name != servers[j].Name {
+			return servers[i].Name < servers[j].Name
+		}
+		return servers[i].ID < servers[j].ID
+	})
+
+	var members []models.TFInstanceGroupMember
+	for idx, s := range servers {
+		if idx >= len(indices) {
+			break
+		}
+		member := models.TFInstanceGroupMember{
+			Index:  types.Int64Value(indices[idx]),
+			ID:     types.StringValue(s.ID),
+			Name:   types.StringValue(s.Name),
+			Status: types.StringValue(s.Status),
+			IPs:    types.MapNull(types.StringType),
+		}
+
+		name := groupMemberName(data.NameTemplate.ValueString(), indices[idx])
+		if renameErr := g.client.Instance.RenameInstance(ctx, region, s.ID, name); renameErr == nil {
+			member.Name = types.StringValue(name)
+		} else if err == nil {
+			err = renameErr
+		}
+
+		if ips, d := g.memberIPs(ctx, region, networkIDs, s.ID); !d.HasError() {
+			member.IPs = ips
+		}
+		members = append(members, member)
+	}
+	return members, err
+}
+
+// deleteMembers deletes the given members and waits until all of them are gone
+func (g *InstanceGroupResource) deleteMembers(ctx context.Context, region string, members []models.TFInstanceGroupMember, timeout time.Duration) error {
+	for _, m := range members {
+		err := g.client.Instance.DeleteInstance(ctx, region, m.ID.ValueString())
+		if err != nil {
+			if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+				continue
+			}
+			return err
+		}
+	}
+
+	return g.client.WaitForCondition(ctx, timeout, func() (bool, error) {
+		for _, m := range members {
+			_, err := g.client.Instance.GetInstance(ctx, region, m.ID.ValueString())
+			if err == nil {
+				return false, nil
+			}
+			if respErr, ok := err.(*api.ResponseError); !ok || respErr.Code != 404 {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// memberIPs returns the ip of the member on each of the group networks keyed by network id
+func (g *InstanceGroupResource) memberIPs(ctx context.Context, region string, networkIDs []string, id string) (types.Map, diag.Diagnostics) {
+	var d diag.Diagnostics
+	attachments, err := g.client.FillNetworkData(ctx, networkIDs, region, id)
+	if err != nil {
+		d.AddError("error fetching network attachments", err.Error())
+		return types.MapNull(types.StringType), d
+	}
+
+	ips := make(map[string]string)
+	for networkID, a := range attachments {
+		ips[networkID] = a.IP
+	}
+	return types.MapValueFrom(ctx, types.StringType, ips)
+}
+
+// missingGroupIndices returns the indices in 1..count which have no member
+func missingGroupIndices(members []models.TFInstanceGroupMember, count int64) []int64 {
+	existing := make(map[int64]bool)
+	for _, m := range members {
+		existing[m.Index.ValueInt64()] = true
+	}
+
+	var ret []int64
+	for idx := int64(1); idx <= count; idx++ {
+		if !existing[idx] {
+			ret = append(ret, idx)
+		}
+	}
+	return ret
+}
+
+// groupMembersMissing reports whether some index in 1..count has no member
+func groupMembersMissing(members []models.TFInstanceGroupMember, count types.Int64) bool {
+	if count.IsUnknown() || count.IsNull() {
+		return false
+	}
+	return len(missingGroupIndices(members, count.ValueInt64())) > 0
+}
+
+func groupMemberName(template string, index int64) string {
+	return fmt.Sprintf(template, index)
+}
+
+func randomGroupToken() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func NewInstanceGroupResource() resource.Resource {
+	return &InstanceGroupResource{}
+}
@@ -0,0 +1,73 @@
+package rs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+func TestMissingGroupIndices(t *testing.T) {
+	members := func(indices ...int64) []models.TFInstanceGroupMember {
+		var ret []models.TFInstanceGroupMember
+		for _, idx := range indices {
+			ret = append(ret, models.TFInstanceGroupMember{Index: types.Int64Value(idx)})
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		members  []models.TFInstanceGroupMember
+		count    int64
+		expected []int64
+	}{
+		{"create", nil, 3, []int64{1, 2, 3}},
+		{"scale up", members(1, 2), 4, []int64{3, 4}},
+		{"replace lost member", members(1, 3), 3, []int64{2}},
+		{"unchanged", members(1, 2, 3), 3, nil},
+		{"scale down", members(1, 2, 3), 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingGroupIndices(tt.members, tt.count)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGroupMembersMissing(t *testing.T) {
+	members := []models.TFInstanceGroupMember{
+		{Index: types.Int64Value(1)},
+		{Index: types.Int64Value(3)},
+	}
+
+	tests := []struct {
+		name     string
+		count    types.Int64
+		expected bool
+	}{
+		{"member deleted out of band", types.Int64Value(3), true},
+		{"scaled down to the remaining members", types.Int64Value(1), false},
+		{"unknown count", types.Int64Unknown(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := groupMembersMissing(members, tt.count); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestGroupMemberName(t *testing.T) {
+	if name := groupMemberName("controller0%d", 2); name != "controller02" {
+		t.Errorf("unexpected name %q", name)
+	}
+	if name := groupMemberName("compute-%02d", 7); name != "compute-07" {
+		t.Errorf("unexpected name %q", name)
+	}
+}
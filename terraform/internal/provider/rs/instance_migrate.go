@@ -0,0 +1,156 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+const (
+	migrationModeCold = "cold"
+	migrationModeLive = "live"
+)
+
+// checkDedicatedServerCapacity reports why flavor does not fit on the dedicated server, the
+// instance being moved is not counted in its usage
+func checkDedicatedServerCapacity(server *api.DedicatedServerList, flavor *api.ServerFlavor) error {
+	if server.Status != "" && !strings.EqualFold(server.Status, "active") {
+		return fmt.Errorf("dedicated server %s is %s", server.ID, server.Status)
+	}
+	if flavor == nil {
+		return nil
+	}
+	if free := server.VCPUs - server.VCPUsUsed; int(flavor.VCPUs) > free {
+		return fmt.Errorf("dedicated server %s has %d free vcpus, flavor %s needs %d", server.ID, free, flavor.ID, flavor.VCPUs)
+	}
+	if free := server.Memory - server.MemoryUsed; flavor.RAM > float64(free) {
+		return fmt.Errorf("dedicated server %s has %d MB of free memory, flavor %s needs %.0f MB", server.ID, free, flavor.ID, flavor.RAM)
+	}
+	return nil
+}
+
+func (i *InstanceResource) checkMigrationTarget(ctx context.Context, region, dedicatedServerID string, flavor *api.ServerFlavor) error {
+	servers, err := i.client.DedicatedServer.ListDedicatedServers(ctx, region)
+	if err != nil {
+		return fmt.Errorf("error fetching dedicated servers: %w", err)
+	}
+	for idx := range servers {
+		if servers[idx].ID == dedicatedServerID {
+			return checkDedicatedServerCapacity(&servers[idx], flavor)
+		}
+	}
+	return fmt.Errorf("dedicated server %s not found", dedicatedServerID)
+}
+
+// waitForMigration waits until the instance is back in status with no task running and placed
+// on the requested server group and dedicated server
+func (i *InstanceResource) waitForMigration(ctx context.Context, region, id, status string, req *api.InstanceMigrateRequest, timeout time.Duration) error {
+	var failure error
+	err := i.client.WaitForCondition(ctx, timeout, func() (bool, error) {
+		detail, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			return false, err
+		}
+		if detail.Status == "ERROR" {
+			failure = fmt.Errorf("instance went into ERROR status while migrating")
+			return true, nil
+		}
+		if detail.Status != status || (detail.TaskState != nil && *detail.TaskState != "") {
+			return false, nil
+		}
+		if req.DedicatedServerID != "" && detail.DedicatedServerID != req.DedicatedServerID {
+			return false, nil
+		}
+		if req.ServerGroupID == "" {
+			return true, nil
+		}
+
+		groups, err := i.client.ServerGroup.ListServerGroups(ctx, region)
+		if err != nil {
+			return false, err
+		}
+		for _, g := range groups {
+			if g.ID == req.ServerGroupID {
+				return slices.Contains(g.Members, id), nil
+			}
+		}
+		return false, nil
+	})
+	if failure != nil {
+		return failure
+	}
+	return err
+}
+
+// handleMigration moves the instance when server_group_id or dedicated_server_id changes
+func (i *InstanceResource) handleMigration(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	req := &api.InstanceMigrateRequest{
+		Live: planData.MigrationMode.ValueString() == migrationModeLive,
+	}
+	if !planData.ServerGroupID.Equal(stateData.ServerGroupID) {
+		if planData.ServerGroupID.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("server_group_id"), "unsupported operation", "instances can be moved to another server group but can not leave it")
+			return
+		}
+		req.ServerGroupID = planData.ServerGroupID.ValueString()
+	}
+	// dedicated_server_id is filled from the api when not configured
+	if !planData.DedicatedServerID.IsNull() && !planData.DedicatedServerID.Equal(stateData.DedicatedServerID) {
+		req.DedicatedServerID = planData.DedicatedServerID.ValueString()
+	}
+	if req.ServerGroupID == "" && req.DedicatedServerID == "" {
+		return
+	}
+
+	status := planData.Status.ValueString()
+	if status == statusRescue {
+		resp.Diagnostics.AddError("invalid migration", "instances in rescue mode can not be migrated")
+		return
+	}
+	if req.Live && status != "ACTIVE" {
+		resp.Diagnostics.AddAttributeError(path.Root("migration_mode"), "invalid migration", "live migration requires the instance to be running, use cold migration instead")
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, id := stateData.Region.ValueString(), stateData.ID.ValueString()
+	if req.DedicatedServerID != "" {
+		detail, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			resp.Diagnostics.AddError("error fetching instance", err.Error())
+			return
+		}
+		if err := i.checkMigrationTarget(ctx, region, req.DedicatedServerID, detail.Flavor); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("dedicated_server_id"), "invalid migration target", err.Error())
+			return
+		}
+	}
+
+	if err := i.client.Instance.MigrateInstance(ctx, region, id, req); err != nil {
+		resp.Diagnostics.AddError("error migrating instance", err.Error())
+		return
+	}
+	if err := i.waitForMigration(ctx, region, id, status, req, updateTimeout); err != nil {
+		resp.Diagnostics.AddError("error waiting for instance migration", err.Error())
+		return
+	}
+
+	if req.ServerGroupID != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("server_group_id"), planData.ServerGroupID)...)
+	}
+	if req.DedicatedServerID != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dedicated_server_id"), planData.DedicatedServerID)...)
+	}
+}
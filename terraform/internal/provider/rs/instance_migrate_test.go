@@ -0,0 +1,33 @@
+package rs
+
+import (
+	"testing"
+
+	"terraform-provider-hashicups-pf/internal/api"
+)
+
+func TestCheckDedicatedServerCapacity(t *testing.T) {
+	flavor := &api.ServerFlavor{ID: "g1-4-2-0", VCPUs: 2, RAM: 4096}
+
+	tests := []struct {
+		name    string
+		server  api.DedicatedServerList
+		flavor  *api.ServerFlavor
+		wantErr bool
+	}{
+		{"fits", api.DedicatedServerList{ID: "ds", Status: "active", VCPUs: 16, VCPUsUsed: 8, Memory: 32768, MemoryUsed: 16384}, flavor, false},
+		{"exact fit", api.DedicatedServerList{ID: "ds", VCPUs: 4, VCPUsUsed: 2, Memory: 8192, MemoryUsed: 4096}, flavor, false},
+		{"not enough vcpus", api.DedicatedServerList{ID: "ds", Status: "ACTIVE", VCPUs: 16, VCPUsUsed: 15, Memory: 32768}, flavor, true},
+		{"not enough memory", api.DedicatedServerList{ID: "ds", VCPUs: 16, Memory: 32768, MemoryUsed: 30000}, flavor, true},
+		{"inactive", api.DedicatedServerList{ID: "ds", Status: "maintenance", VCPUs: 16, Memory: 32768}, flavor, true},
+		{"unknown flavor", api.DedicatedServerList{ID: "ds", VCPUs: 1, VCPUsUsed: 1}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkDedicatedServerCapacity(&tt.server, tt.flavor)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
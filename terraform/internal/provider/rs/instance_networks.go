@@ -0,0 +1,138 @@
+package rs
+
+import (
+	"context"
+	"errors"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// planNetworkAttachments fills the unknown computed values of the planned attachments from the
+// attachment in state on the same network. A changed subnet needs a new port so everything
+// stays unknown, a changed ip may need one so port_id and ptr stay unknown
+func planNetworkAttachments(plan, state []models.TFNetworkAttachment) []models.TFNetworkAttachment {
+	byNetwork := make(map[string]models.TFNetworkAttachment)
+	for _, s := range state {
+		byNetwork[s.NetworkID.ValueString()] = s
+	}
+
+	ret := make([]models.TFNetworkAttachment, 0, len(plan))
+	for _, p := range plan {
+		s, ok := byNetwork[p.NetworkID.ValueString()]
+		if !ok || p.NetworkID.IsUnknown() || (!p.SubnetID.IsUnknown() && !p.SubnetID.Equal(s.SubnetID)) {
+			ret = append(ret, p)
+			continue
+		}
+
+		ipChanged := !p.IP.IsUnknown() && !p.IP.Equal(s.IP)
+		if p.SubnetID.IsUnknown() {
+			p.SubnetID = s.SubnetID
+		}
+		if p.IP.IsUnknown() {
+			p.IP = s.IP
+		}
+		if p.IsPublic.IsUnknown() {
+			p.IsPublic = s.IsPublic
+		}
+		if p.PortID.IsUnknown() && !ipChanged {
+			p.PortID = s.PortID
+		}
+		if p.PTR.IsUnknown() && !ipChanged {
+			p.PTR = s.PTR
+		}
+		ret = append(ret, p)
+	}
+	return ret
+}
+
+// networksModifier keys the planned networks by network_id so an attachment keeps its port
+// and addresses in the plan when only its position or an in-place setting changes
+type networksModifier struct{}
+
+func (m networksModifier) Description(context.Context) string {
+	return ""
+}
+
+func (m networksModifier) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+func (m networksModifier) PlanModifySet(ctx context.Context, req planmodifier.SetRequest, resp *planmodifier.SetResponse) {
+	if req.StateValue.IsNull() || req.PlanValue.IsNull() || req.PlanValue.IsUnknown() {
+		return
+	}
+
+	var plan, state []models.TFNetworkAttachment
+	resp.Diagnostics.Append(req.PlanValue.ElementsAs(ctx, &plan, false)...)
+	resp.Diagnostics.Append(req.StateValue.ElementsAs(ctx, &state, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planned, d := types.SetValueFrom(ctx, req.PlanValue.ElementType(ctx), planNetworkAttachments(plan, state))
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.PlanValue = planned
+}
+
+// portUpdateUnsupported reports whether the api refused an in-place port update
+func portUpdateUnsupported(err error) bool {
+	var respErr *api.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	switch respErr.Code {
+	case 404, 405, 501:
+		return true
+	}
+	return false
+}
+
+// updateNetworkAttachment applies the changes from current to planned on the existing port.
+// It returns false when the port has to be reattached instead, before changing anything
+func (i *InstanceResource) updateNetworkAttachment(ctx context.Context, region string, current, planned models.TFNetworkAttachment) (models.TFNetworkAttachment, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	if !planned.SubnetID.IsUnknown() && !planned.SubnetID.Equal(current.SubnetID) {
+		return current, false, diags
+	}
+
+	updated := current
+	if !planned.IP.IsUnknown() && !planned.IP.Equal(current.IP) {
+		err := i.client.Subnet.UpdatePortIP(ctx, region, current.PortID.ValueString(), current.SubnetID.ValueString(), planned.IP.ValueString())
+		if portUpdateUnsupported(err) {
+			return current, false, diags
+		}
+		if err != nil {
+			diags.AddError("error changing port ip", err.Error())
+			return current, true, diags
+		}
+		updated.IP = planned.IP
+		updated.PTR = types.StringValue("")
+	}
+
+	if !planned.PortSecurityEnabled.Equal(current.PortSecurityEnabled) {
+		var err error
+		if planned.PortSecurityEnabled.ValueBool() {
+			err = i.client.Subnet.EnablePortSecurity(ctx, region, current.NetworkID.ValueString(), current.PortID.ValueString())
+		} else {
+			err = i.client.Subnet.DisablePortSecurity(ctx, region, current.NetworkID.ValueString(), current.PortID.ValueString())
+		}
+		if err != nil {
+			diags.AddError("error changing port security", err.Error())
+			return updated, true, diags
+		}
+		updated.PortSecurityEnabled = planned.PortSecurityEnabled
+	}
+
+	if !planned.AllowedAddressPairs.Equal(current.AllowedAddressPairs) {
+		updated.AllowedAddressPairs = planned.AllowedAddressPairs
+		diags.Append(i.setAllowedAddressPairs(ctx, region, updated)...)
+	}
+	return updated, true, diags
+}
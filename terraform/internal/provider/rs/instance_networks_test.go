@@ -0,0 +1,83 @@
+package rs
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+func TestPlanNetworkAttachments(t *testing.T) {
+	attached := func(network, subnet, ip, port string) models.TFNetworkAttachment {
+		return models.TFNetworkAttachment{
+			NetworkID:           types.StringValue(network),
+			SubnetID:            types.StringValue(subnet),
+			IP:                  types.StringValue(ip),
+			PortID:              types.StringValue(port),
+			IsPublic:            types.BoolValue(false),
+			PortSecurityEnabled: types.BoolValue(true),
+			PTR:                 types.StringValue(""),
+			AllowedAddressPairs: types.SetNull(types.StringType),
+		}
+	}
+	planned := func(network string) models.TFNetworkAttachment {
+		return models.TFNetworkAttachment{
+			NetworkID:           types.StringValue(network),
+			SubnetID:            types.StringUnknown(),
+			IP:                  types.StringUnknown(),
+			PortID:              types.StringUnknown(),
+			IsPublic:            types.BoolUnknown(),
+			PortSecurityEnabled: types.BoolValue(true),
+			PTR:                 types.StringUnknown(),
+			AllowedAddressPairs: types.SetNull(types.StringType),
+		}
+	}
+	state := []models.TFNetworkAttachment{
+		attached("net-a", "sub-a", "10.0.0.5", "port-a"),
+		attached("net-b", "sub-b", "10.1.0.5", "port-b"),
+	}
+
+	t.Run("reordered", func(t *testing.T) {
+		got := planNetworkAttachments([]models.TFNetworkAttachment{planned("net-b"), planned("net-a")}, state)
+		if !models.NetworkAttachmentsEqual(got, state) {
+			t.Errorf("expected state values, got %v", got)
+		}
+	})
+
+	t.Run("port security changed", func(t *testing.T) {
+		p := planned("net-a")
+		p.PortSecurityEnabled = types.BoolValue(false)
+		got := planNetworkAttachments([]models.TFNetworkAttachment{p}, state)[0]
+		if got.PortID.ValueString() != "port-a" || got.IP.ValueString() != "10.0.0.5" {
+			t.Errorf("expected port and ip to be kept, got %v", got)
+		}
+	})
+
+	t.Run("ip changed", func(t *testing.T) {
+		p := planned("net-a")
+		p.IP = types.StringValue("10.0.0.9")
+		got := planNetworkAttachments([]models.TFNetworkAttachment{p}, state)[0]
+		if got.IP.ValueString() != "10.0.0.9" || got.SubnetID.ValueString() != "sub-a" {
+			t.Errorf("expected new ip on the same subnet, got %v", got)
+		}
+		if !got.PortID.IsUnknown() || !got.PTR.IsUnknown() {
+			t.Errorf("expected port_id and ptr to stay unknown, got %v", got)
+		}
+	})
+
+	t.Run("subnet changed", func(t *testing.T) {
+		p := planned("net-a")
+		p.SubnetID = types.StringValue("sub-c")
+		got := planNetworkAttachments([]models.TFNetworkAttachment{p}, state)[0]
+		if !got.IP.IsUnknown() || !got.PortID.IsUnknown() {
+			t.Errorf("expected a new port, got %v", got)
+		}
+	})
+
+	t.Run("new network", func(t *testing.T) {
+		got := planNetworkAttachments([]models.TFNetworkAttachment{planned("net-c")}, state)[0]
+		if !got.PortID.IsUnknown() {
+			t.Errorf("expected port_id to stay unknown, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package rs
+
+import (
+	"context"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// storePassword reports whether any form of the password is kept in state, a missing value
+// of older states counts as stored
+func storePassword(data *models.TFInstanceResourceModel) bool {
+	return data.StorePassword.IsNull() || data.StorePassword.IsUnknown() || data.StorePassword.ValueBool()
+}
+
+// setInstancePassword stores password in data either as is, encrypted with pgp_key, or not
+// at all depending on store_password
+func setInstancePassword(data *models.TFInstanceResourceModel, password string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	data.Password = types.StringNull()
+	data.EncryptedPassword = types.StringNull()
+	switch {
+	case !storePassword(data):
+	case !data.PGPKey.IsNull():
+		if password == "" {
+			break
+		}
+		encrypted, err := utl.EncryptWithPGPKey(data.PGPKey.ValueString(), password)
+		if err != nil {
+			diags.AddAttributeError(path.Root("pgp_key"), "error encrypting password", err.Error())
+			break
+		}
+		data.EncryptedPassword = types.StringValue(encrypted)
+	default:
+		data.Password = types.StringValue(password)
+	}
+	return diags
+}
+
+// handlePasswordStorage stores the password again when pgp_key or store_password changes,
+// the password is fetched from the api when it is not in state
+func (i *InstanceResource) handlePasswordStorage(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	// a rebuild already stored the new password
+	if rebuildRequested(stateData, planData) {
+		return
+	}
+
+	if planData.PGPKey.Equal(stateData.PGPKey) && storePassword(planData) == storePassword(stateData) {
+		if planData.Password.IsUnknown() {
+			planData.Password = stateData.Password
+		}
+		if planData.EncryptedPassword.IsUnknown() {
+			planData.EncryptedPassword = stateData.EncryptedPassword
+		}
+		return
+	}
+
+	password := stateData.Password.ValueString()
+	if password == "" && storePassword(planData) {
+		det, err := i.client.Instance.GetInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("error fetching instance password", err.Error())
+			return
+		}
+		password = det.Password
+		if password == "" {
+			resp.Diagnostics.AddWarning("instance password not available", "the api no longer returns the password of this instance, nothing is stored")
+		}
+	}
+
+	resp.Diagnostics.Append(setInstancePassword(planData, password)...)
+}
+
+// passwordStorageModifier plans password and encrypted_password according to pgp_key and
+// store_password
+type passwordStorageModifier struct {
+	encrypted bool
+}
+
+func (m passwordStorageModifier) Description(context.Context) string {
+	return ""
+}
+
+func (m passwordStorageModifier) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+func (m passwordStorageModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var pgpKey types.String
+	var store types.Bool
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("pgp_key"), &pgpKey)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("store_password"), &store)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !store.IsNull() && !store.IsUnknown() && !store.ValueBool() {
+		if !m.encrypted && !pgpKey.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("pgp_key"), "invalid pgp_key", "pgp_key can not be used when store_password is false")
+		}
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	if pgpKey.IsUnknown() {
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+
+	if m.encrypted != !pgpKey.IsNull() {
+		resp.PlanValue = types.StringNull()
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		return
+	}
+
+	// a new key encrypts the password again, a removed key brings the plain password back
+	var statePGPKey types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("pgp_key"), &statePGPKey)...)
+	if !statePGPKey.Equal(pgpKey) {
+		resp.PlanValue = types.StringUnknown()
+	}
+}
@@ -0,0 +1,192 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+)
+
+const (
+	ipVersion4 = "4"
+	ipVersion6 = "6"
+)
+
+// publicPort is a port of an instance on a public network
+type publicPort struct {
+	NetworkID string
+	SubnetID  string
+	PortID    string
+}
+
+func isPublicNetwork(n *api.Network) bool {
+	return n.RouterExternal != nil && *n.RouterExternal
+}
+
+// findPublicPorts returns the public ports of serverID keyed by ip version. A dual stack
+// port shows up under both versions
+func findPublicPorts(networks map[string]*api.Network, serverID string) map[string]publicPort {
+	ret := make(map[string]publicPort)
+	for _, n := range networks {
+		if !isPublicNetwork(n) {
+			continue
+		}
+		for _, s := range n.Subnets {
+			for _, srv := range s.Servers {
+				if srv.ID != serverID {
+					continue
+				}
+				for _, ip := range srv.IPs {
+					if ip.SubnetID != s.ID {
+						continue
+					}
+					ret[s.IPVersion] = publicPort{NetworkID: n.ID, SubnetID: s.ID, PortID: ip.PortID}
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// findPublicSubnet returns the first public subnet of the given ip version
+func findPublicSubnet(networks map[string]*api.Network, version string) (*api.SubnetDetails, bool) {
+	for _, n := range networks {
+		if !isPublicNetwork(n) {
+			continue
+		}
+		for _, s := range n.Subnets {
+			if s.IPVersion == version {
+				return s, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// handlePublicIPs attaches or detaches the public network when enable_ipv4 or enable_ipv6
+// changes. It runs before the floating ip step, which needs the public ipv4 to be gone. A
+// floating ip removed in the same apply is detached here before the public ipv4 is attached
+func (i *InstanceResource) handlePublicIPs(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	want := map[string]bool{
+		ipVersion4: planData.EnableIPv4.ValueBool(),
+		ipVersion6: planData.EnableIPv6.ValueBool(),
+	}
+	if want[ipVersion4] == stateData.EnableIPv4.ValueBool() && want[ipVersion6] == stateData.EnableIPv6.ValueBool() {
+		return
+	}
+
+	if want[ipVersion4] && !planData.FloatingIP.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("enable_ipv4"), "invalid operation", "floating ip can only be attached to servers without public ip, remove floating_ip before enabling ipv4")
+		return
+	}
+
+	// the public ipv4 can only be attached once the floating ip being removed is gone
+	if want[ipVersion4] && !stateData.EnableIPv4.ValueBool() && !stateData.FloatingIP.IsNull() {
+		nets, d := stateData.GetNetworkAttachments(ctx)
+		resp.Diagnostics.Append(d...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(i.detachFloatingIP(ctx, stateData, nets)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(stateData.SetFloatingIPAttachment(ctx, nil)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("floating_ip"), stateData.FloatingIP)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, id := stateData.Region.ValueString(), stateData.ID.ValueString()
+	networks, err := i.client.Subnet.GetAllNetworks(ctx, region)
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching networks", err.Error())
+		return
+	}
+
+	// detaching a dual stack port drops both versions, the other one is attached again below
+	detached := make(map[string]bool)
+	for _, version := range []string{ipVersion4, ipVersion6} {
+		port, ok := findPublicPorts(networks, id)[version]
+		if want[version] || !ok || detached[port.PortID] {
+			continue
+		}
+		err = i.client.Subnet.DetachServerFromNetwork(ctx, region, port.PortID, id)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("error detaching public ipv%s", version), err.Error())
+			return
+		}
+		detached[port.PortID] = true
+	}
+
+	if len(detached) > 0 {
+		networks, err = i.client.Subnet.GetAllNetworks(ctx, region)
+		if err != nil {
+			resp.Diagnostics.AddError("error fetching networks", err.Error())
+			return
+		}
+	}
+
+	ports := findPublicPorts(networks, id)
+	for _, version := range []string{ipVersion4, ipVersion6} {
+		if _, ok := ports[version]; !want[version] || ok {
+			continue
+		}
+		subnet, ok := findPublicSubnet(networks, version)
+		if !ok {
+			resp.Diagnostics.AddError(fmt.Sprintf("error attaching public ipv%s", version), "region has no public network for this ip version")
+			return
+		}
+		_, err = i.client.Subnet.AttachServerToNetwork(ctx, region, subnet.NetworkID, &api.AttachServerToNetworkRequest{
+			ServerID:           id,
+			SubnetID:           subnet.ID,
+			EnablePortSecurity: true,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("error attaching public ipv%s", version), err.Error())
+			return
+		}
+
+		// a dual stack subnet may have brought the other version along
+		networks, err = i.client.Subnet.GetAllNetworks(ctx, region)
+		if err != nil {
+			resp.Diagnostics.AddError("error fetching networks", err.Error())
+			return
+		}
+		ports = findPublicPorts(networks, id)
+	}
+
+	err = i.client.WaitForCondition(ctx, updateTimeout, func() (bool, error) {
+		info, err := i.client.FIPClient.GetServerIPInfo(ctx, region)
+		if err != nil {
+			return false, err
+		}
+		if sInfo, ok := info[id]; !ok || sInfo.HasPublicIP != want[ipVersion4] {
+			return false, nil
+		}
+
+		networks, err := i.client.Subnet.GetAllNetworks(ctx, region)
+		if err != nil {
+			return false, err
+		}
+		_, hasIPv6 := findPublicPorts(networks, id)[ipVersion6]
+		return hasIPv6 == want[ipVersion6], nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("error waiting for public ips", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("enable_ipv4"), planData.EnableIPv4)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("enable_ipv6"), planData.EnableIPv6)...)
+}
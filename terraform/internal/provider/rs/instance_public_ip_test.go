@@ -0,0 +1,55 @@
+package rs
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-hashicups-pf/internal/api"
+)
+
+func TestFindPublicPorts(t *testing.T) {
+	external, internal := true, false
+	server := func(id string, ips ...*api.FullIP) *api.NetworkServer {
+		return &api.NetworkServer{ID: id, IPs: ips}
+	}
+	networks := map[string]*api.Network{
+		"public": {
+			ID:             "public",
+			RouterExternal: &external,
+			Subnets: []*api.SubnetDetails{
+				{ID: "v4", IPVersion: "4", Servers: []*api.NetworkServer{
+					server("srv", &api.FullIP{SubnetID: "v4", PortID: "port-1"}),
+					server("other", &api.FullIP{SubnetID: "v4", PortID: "port-2"}),
+				}},
+				{ID: "v6", IPVersion: "6", Servers: []*api.NetworkServer{
+					server("srv", &api.FullIP{SubnetID: "v6", PortID: "port-1"}),
+				}},
+			},
+		},
+		"private": {
+			ID:             "private",
+			RouterExternal: &internal,
+			Subnets: []*api.SubnetDetails{
+				{ID: "p", IPVersion: "4", Servers: []*api.NetworkServer{
+					server("srv", &api.FullIP{SubnetID: "p", PortID: "port-3"}),
+				}},
+			},
+		},
+	}
+
+	expected := map[string]publicPort{
+		"4": {NetworkID: "public", SubnetID: "v4", PortID: "port-1"},
+		"6": {NetworkID: "public", SubnetID: "v6", PortID: "port-1"},
+	}
+	if got := findPublicPorts(networks, "srv"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+	if got := findPublicPorts(networks, "missing"); len(got) != 0 {
+		t.Errorf("expected no ports, got %v", got)
+	}
+
+	subnet, ok := findPublicSubnet(networks, "6")
+	if !ok || subnet.ID != "v6" {
+		t.Errorf("expected public ipv6 subnet, got %v", subnet)
+	}
+}
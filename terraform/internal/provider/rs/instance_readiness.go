@@ -0,0 +1,176 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+const defaultReadinessTimeout = "5m"
+
+func readinessSchema() schema.SingleNestedAttribute {
+	timeout := func() schema.StringAttribute {
+		return schema.StringAttribute{
+			Optional: true,
+			Computed: true,
+			Default:  stringdefault.StaticString(defaultReadinessTimeout),
+			Validators: []validator.String{
+				utl.DurationValidator(),
+			},
+		}
+	}
+	port := func(required bool) schema.Int64Attribute {
+		return schema.Int64Attribute{
+			Required: required,
+			Optional: !required,
+			Validators: []validator.Int64{
+				int64validator.Between(1, 65535),
+			},
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"tcp": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"port": port(true),
+					"network_id": schema.StringAttribute{
+						Optional: true,
+					},
+					"timeout": timeout(),
+				},
+			},
+			"http": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"scheme": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("http"),
+						Validators: []validator.String{
+							stringvalidator.OneOf("http", "https"),
+						},
+					},
+					"port": port(false),
+					"path": schema.StringAttribute{
+						Optional: true,
+						Computed: true,
+						Default:  stringdefault.StaticString("/"),
+					},
+					"network_id": schema.StringAttribute{
+						Optional: true,
+					},
+					"timeout": timeout(),
+				},
+			},
+			"console": schema.SingleNestedAttribute{
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"marker": schema.StringAttribute{
+						Required: true,
+						Validators: []validator.String{
+							stringvalidator.LengthAtLeast(1),
+						},
+					},
+					"timeout": timeout(),
+				},
+			},
+		},
+	}
+}
+
+// waitForReadiness runs the configured readiness checks one after another
+func (i *InstanceResource) waitForReadiness(ctx context.Context, data *models.TFInstanceResourceModel, nets []models.TFNetworkAttachment) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	readiness, d := data.GetReadiness(ctx)
+	diags.Append(d...)
+	if diags.HasError() || readiness == nil {
+		return diags
+	}
+
+	if tcp := readiness.TCP; tcp != nil {
+		ip, err := readinessIP(tcp.NetworkID.ValueString(), nets)
+		if err != nil {
+			diags.AddError("tcp readiness check failed", err.Error())
+			return diags
+		}
+		address := net.JoinHostPort(ip, strconv.FormatInt(tcp.Port.ValueInt64(), 10))
+		if err := utl.WaitForTCP(ctx, address, readinessTimeout(tcp.Timeout.ValueString())); err != nil {
+			diags.AddError("tcp readiness check failed", fmt.Sprintf("%s: %s", address, err))
+			return diags
+		}
+	}
+
+	if h := readiness.HTTP; h != nil {
+		ip, err := readinessIP(h.NetworkID.ValueString(), nets)
+		if err != nil {
+			diags.AddError("http readiness check failed", err.Error())
+			return diags
+		}
+		host := ip
+		if !h.Port.IsNull() {
+			host = net.JoinHostPort(ip, strconv.FormatInt(h.Port.ValueInt64(), 10))
+		} else if net.ParseIP(ip).To4() == nil {
+			host = "[" + ip + "]"
+		}
+		path := h.Path.ValueString()
+		if len(path) == 0 || path[0] != '/' {
+			path = "/" + path
+		}
+		url := fmt.Sprintf("%s://%s%s", h.Scheme.ValueString(), host, path)
+		if err := utl.WaitForHTTP(ctx, url, readinessTimeout(h.Timeout.ValueString())); err != nil {
+			diags.AddError("http readiness check failed", fmt.Sprintf("%s: %s", url, err))
+			return diags
+		}
+	}
+
+	if c := readiness.Console; c != nil {
+		getLog := func(ctx context.Context) (string, error) {
+			return i.client.Instance.GetConsoleLog(ctx, data.Region.ValueString(), data.ID.ValueString())
+		}
+		if err := utl.WaitForConsoleMarker(ctx, getLog, c.Marker.ValueString(), readinessTimeout(c.Timeout.ValueString())); err != nil {
+			diags.AddError("console readiness check failed", err.Error())
+			return diags
+		}
+	}
+	return diags
+}
+
+// readinessIP returns the ip of the instance on networkID, or on the first network
+// with an ip when it is empty
+func readinessIP(networkID string, nets []models.TFNetworkAttachment) (string, error) {
+	for _, n := range nets {
+		if n.IP.ValueString() == "" {
+			continue
+		}
+		if networkID == "" || n.NetworkID.ValueString() == networkID {
+			return n.IP.ValueString(), nil
+		}
+	}
+	if networkID != "" {
+		return "", fmt.Errorf("instance has no ip on network %s", networkID)
+	}
+	return "", fmt.Errorf("instance has no ip")
+}
+
+func readinessTimeout(timeout string) time.Duration {
+	d, err := time.ParseDuration(timeout)
+	if err != nil || d <= 0 {
+		d, _ = time.ParseDuration(defaultReadinessTimeout)
+	}
+	return d
+}
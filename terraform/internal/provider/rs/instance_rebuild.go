@@ -0,0 +1,113 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// rebuildRequested reports whether the image change in the plan is applied by rebuilding
+func rebuildRequested(stateData, planData *models.TFInstanceResourceModel) bool {
+	return planData.RebuildOnImageChange.ValueBool() && !planData.ImageID.Equal(stateData.ImageID)
+}
+
+// handleRebuild rebuilds the instance with the new image, ports, volumes, the floating ip
+// and security groups are kept while init script and user data run again
+func (i *InstanceResource) handleRebuild(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	if !rebuildRequested(stateData, planData) {
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 20*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	initScript := planData.InitScript.ValueString()
+	if !planData.RenderedUserData.IsNull() && !planData.RenderedUserData.IsUnknown() {
+		initScript = planData.RenderedUserData.ValueString()
+	}
+
+	region, id := stateData.Region.ValueString(), stateData.ID.ValueString()
+	detail, err := i.client.Instance.RebuildInstance(ctx, region, id, planData.ImageID.ValueString(), initScript)
+	if err != nil {
+		resp.Diagnostics.AddError("error rebuilding instance", err.Error())
+		return
+	}
+
+	planData.Password, planData.EncryptedPassword = stateData.Password, stateData.EncryptedPassword
+	if detail.Password != "" {
+		resp.Diagnostics.Append(setInstancePassword(planData, detail.Password)...)
+	}
+
+	// the old password is gone once the rebuild started, keep the new one even if waiting fails
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("image_id"), planData.ImageID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("password"), planData.Password)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("encrypted_password"), planData.EncryptedPassword)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var rebuildErr error
+	err = i.client.WaitForCondition(ctx, updateTimeout, func() (bool, error) {
+		det, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			return false, err
+		}
+		if det.Status == "ERROR" {
+			rebuildErr = fmt.Errorf("instance status transitioned into invalid state ERROR")
+			return true, nil
+		}
+		if (det.Status == "ACTIVE" || det.Status == "SHUTOFF") && (det.TaskState == nil || *det.TaskState == "") {
+			planData.Status = types.StringValue(det.Status)
+			return true, nil
+		}
+		return false, nil
+	})
+	if err == nil {
+		err = rebuildErr
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error rebuilding instance", err.Error())
+		return
+	}
+	stateData.Status = planData.Status
+}
+
+// rebuildPasswordModifier marks the password unknown when the instance is going to be
+// rebuilt, since the rebuild returns a new one
+type rebuildPasswordModifier struct{}
+
+func (m rebuildPasswordModifier) Description(context.Context) string {
+	return ""
+}
+
+func (m rebuildPasswordModifier) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+func (m rebuildPasswordModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var rebuild types.Bool
+	var planImage, stateImage types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rebuild_on_image_change"), &rebuild)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("image_id"), &planImage)...)
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("image_id"), &stateImage)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if rebuild.ValueBool() && !planImage.Equal(stateImage) {
+		resp.PlanValue = types.StringUnknown()
+	}
+}
@@ -0,0 +1,122 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const statusRescue = "RESCUE"
+
+func rescueSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				Required: true,
+			},
+			"image_id": schema.StringAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+// rescueEnabled reports whether the instance is configured to be in rescue mode
+func rescueEnabled(rescue *models.TFRescue) bool {
+	return rescue != nil && rescue.Enabled.ValueBool()
+}
+
+// enterRescue powers the instance on if needed and boots it into rescue mode, networks and
+// volumes stay attached
+func (i *InstanceResource) enterRescue(ctx context.Context, region, id string, rescue *models.TFRescue, timeout time.Duration) error {
+	if _, err := i.setPowerState(ctx, region, id, powerStateRunning, timeout); err != nil {
+		return fmt.Errorf("error powering on instance: %w", err)
+	}
+	if err := i.client.Instance.RescueInstance(ctx, region, id, rescue.ImageID.ValueString()); err != nil {
+		return err
+	}
+	return i.waitForStatus(ctx, region, id, statusRescue, timeout)
+}
+
+// exitRescue boots the instance from its own disk again
+func (i *InstanceResource) exitRescue(ctx context.Context, region, id string, timeout time.Duration) error {
+	if err := i.client.Instance.UnrescueInstance(ctx, region, id); err != nil {
+		return err
+	}
+	return i.waitForStatus(ctx, region, id, "ACTIVE", timeout)
+}
+
+func (i *InstanceResource) handleRescue(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	planRescue, d := planData.GetRescue(ctx)
+	resp.Diagnostics.Append(d...)
+	stateRescue, d := stateData.GetRescue(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	want := rescueEnabled(planRescue)
+	inRescue := planData.Status.ValueString() == statusRescue
+	// another rescue image needs a round trip through the instance's own disk
+	imageChanged := want && inRescue && stateRescue != nil && !planRescue.ImageID.Equal(stateRescue.ImageID)
+	if want == inRescue && !imageChanged {
+		return
+	}
+
+	if want && desiredPowerState(stateData, planData) == powerStateStopped {
+		resp.Diagnostics.AddAttributeError(path.Root("rescue"), "invalid rescue mode", "rescue mode requires power_state to be running")
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 10*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, id := stateData.Region.ValueString(), stateData.ID.ValueString()
+	if inRescue {
+		if err := i.exitRescue(ctx, region, id, updateTimeout); err != nil {
+			resp.Diagnostics.AddError("error leaving rescue mode", err.Error())
+			return
+		}
+		planData.Status = types.StringValue("ACTIVE")
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("status"), planData.Status)...)
+	}
+
+	if want {
+		if err := i.enterRescue(ctx, region, id, planRescue, updateTimeout); err != nil {
+			resp.Diagnostics.AddError("error entering rescue mode", err.Error())
+			return
+		}
+		planData.Status = types.StringValue(statusRescue)
+	}
+}
+
+// rescueFromStatus reflects rescue mode entered or left outside of terraform, transitional
+// statuses leave rescue untouched
+func rescueFromStatus(rescue *models.TFRescue, status string) *models.TFRescue {
+	switch status {
+	case statusRescue:
+		if rescueEnabled(rescue) {
+			return rescue
+		}
+		ret := &models.TFRescue{Enabled: types.BoolValue(true), ImageID: types.StringNull()}
+		if rescue != nil {
+			ret.ImageID = rescue.ImageID
+		}
+		return ret
+	case "ACTIVE", "SHUTOFF":
+		if rescueEnabled(rescue) {
+			return &models.TFRescue{Enabled: types.BoolValue(false), ImageID: rescue.ImageID}
+		}
+	}
+	return rescue
+}
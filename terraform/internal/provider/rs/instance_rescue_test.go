@@ -0,0 +1,41 @@
+package rs
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+func TestRescueFromStatus(t *testing.T) {
+	rescue := func(enabled bool, image string) *models.TFRescue {
+		ret := &models.TFRescue{Enabled: types.BoolValue(enabled), ImageID: types.StringNull()}
+		if image != "" {
+			ret.ImageID = types.StringValue(image)
+		}
+		return ret
+	}
+
+	tests := []struct {
+		name     string
+		rescue   *models.TFRescue
+		status   string
+		expected *models.TFRescue
+	}{
+		{"not configured", nil, "ACTIVE", nil},
+		{"rescued outside", nil, "RESCUE", rescue(true, "")},
+		{"rescued outside while disabled", rescue(false, "img"), "RESCUE", rescue(true, "img")},
+		{"still rescued", rescue(true, "img"), "RESCUE", rescue(true, "img")},
+		{"unrescued outside", rescue(true, "img"), "ACTIVE", rescue(false, "img")},
+		{"transitional status", rescue(true, "img"), "REBOOT", rescue(true, "img")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rescueFromStatus(tt.rescue, tt.status)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
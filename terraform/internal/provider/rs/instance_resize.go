@@ -0,0 +1,215 @@
+package rs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// flavorResizePrivateKey holds the progress of a flavor resize in private state, it is only
+// left behind when a resize could not be finished or rolled back
+const flavorResizePrivateKey = "flavor_resize"
+
+const (
+	resizeStepPowerOff = "power_off"
+	resizeStepResize   = "resize"
+	resizeStepConfirm  = "confirm"
+	resizeStepPowerOn  = "power_on"
+)
+
+// errResizeProgress rolls the resize back once the instance is powered off, continuing
+// without recorded progress would hide an interrupted resize from the next read
+var errResizeProgress = errors.New("resize progress could not be saved")
+
+type flavorResizeProgress struct {
+	FromFlavor string `json:"from_flavor"`
+	ToFlavor   string `json:"to_flavor"`
+	PowerState string `json:"power_state"`
+	Step       string `json:"step"`
+}
+
+// privateStateSetter is implemented by the private state of update and read responses
+type privateStateSetter interface {
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func (p *flavorResizeProgress) save(ctx context.Context, private privateStateSetter, step string) diag.Diagnostics {
+	p.Step = step
+	data, err := json.Marshal(p)
+	if err != nil {
+		var d diag.Diagnostics
+		d.AddError("error saving resize progress", err.Error())
+		return d
+	}
+	return private.SetKey(ctx, flavorResizePrivateKey, data)
+}
+
+// handleFlavorResize powers the instance off, resizes it, confirms the new flavor and
+// restores the desired power state. If a step fails the previous flavor and power state
+// are restored
+func (i *InstanceResource) handleFlavorResize(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	if planData.FlavorID.Equal(stateData.FlavorID) {
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	region, id := stateData.Region.ValueString(), stateData.ID.ValueString()
+	progress := &flavorResizeProgress{
+		FromFlavor: stateData.FlavorID.ValueString(),
+		ToFlavor:   planData.FlavorID.ValueString(),
+		PowerState: powerStateFromStatus(stateData.Status.ValueString(), powerStateRunning),
+	}
+	fail := func(err error) {
+		i.rollbackFlavorResize(ctx, region, id, progress, err, updateTimeout, resp)
+	}
+
+	resp.Diagnostics.AddWarning("instance power off", "during resize operation your instance powers off")
+	resp.Diagnostics.Append(progress.save(ctx, resp.Private, resizeStepPowerOff)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	status, err := i.setPowerState(ctx, region, id, powerStateStopped, updateTimeout)
+	if err != nil {
+		fail(err)
+		return
+	}
+	planData.Status = types.StringValue(status)
+
+	resp.Diagnostics.Append(progress.save(ctx, resp.Private, resizeStepResize)...)
+	if resp.Diagnostics.HasError() {
+		fail(errResizeProgress)
+		return
+	}
+	err = i.client.Instance.ResizeInstance(ctx, region, id, progress.ToFlavor)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	resp.Diagnostics.Append(progress.save(ctx, resp.Private, resizeStepConfirm)...)
+	if resp.Diagnostics.HasError() {
+		fail(errResizeProgress)
+		return
+	}
+	status, err = i.waitForFlavor(ctx, region, id, progress.ToFlavor, updateTimeout)
+	if err != nil {
+		fail(err)
+		return
+	}
+	planData.Status = types.StringValue(status)
+
+	// a stopped instance stays stopped after resize, only bring it back if it is desired to run
+	if desiredPowerState(stateData, planData) == powerStateRunning {
+		resp.Diagnostics.Append(progress.save(ctx, resp.Private, resizeStepPowerOn)...)
+		if resp.Diagnostics.HasError() {
+			fail(errResizeProgress)
+			return
+		}
+		status, err = i.setPowerState(ctx, region, id, powerStateRunning, updateTimeout)
+		if err != nil {
+			fail(err)
+			return
+		}
+		planData.Status = types.StringValue(status)
+	}
+
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, flavorResizePrivateKey, nil)...)
+}
+
+// rollbackFlavorResize restores the flavor and power state the instance had before the
+// resize and records the actual flavor and status in state
+func (i *InstanceResource) rollbackFlavorResize(ctx context.Context, region, id string, progress *flavorResizeProgress, stepErr error, timeout time.Duration, resp *resource.UpdateResponse) {
+	summary := fmt.Sprintf("resizing instance from %s to %s failed at step %s: %s", progress.FromFlavor, progress.ToFlavor, progress.Step, stepErr)
+
+	rollbackErr := func() error {
+		det, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			return err
+		}
+		if det.Flavor != nil && det.Flavor.ID != progress.FromFlavor {
+			if _, err := i.setPowerState(ctx, region, id, powerStateStopped, timeout); err != nil {
+				return fmt.Errorf("power off: %w", err)
+			}
+			if err := i.client.Instance.ResizeInstance(ctx, region, id, progress.FromFlavor); err != nil {
+				return fmt.Errorf("resize: %w", err)
+			}
+			if _, err := i.waitForFlavor(ctx, region, id, progress.FromFlavor, timeout); err != nil {
+				return fmt.Errorf("confirm: %w", err)
+			}
+		}
+		if _, err := i.setPowerState(ctx, region, id, progress.PowerState, timeout); err != nil {
+			return fmt.Errorf("restore power state: %w", err)
+		}
+		return nil
+	}()
+
+	if rollbackErr != nil {
+		resp.Diagnostics.AddError("error resizing instance", fmt.Sprintf("%s, rolling back to flavor %s failed at %s", summary, progress.FromFlavor, rollbackErr))
+	} else {
+		resp.Diagnostics.AddError("error resizing instance", fmt.Sprintf("%s, rolled back to flavor %s and power state %s", summary, progress.FromFlavor, progress.PowerState))
+		resp.Diagnostics.Append(resp.Private.SetKey(ctx, flavorResizePrivateKey, nil)...)
+	}
+
+	det, err := i.client.Instance.GetInstance(ctx, region, id)
+	if err != nil {
+		return
+	}
+	if det.Flavor != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("flavor_id"), det.Flavor.ID)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("status"), det.Status)...)
+}
+
+// waitForFlavor waits until the instance reports the flavor and settled in a stable status
+func (i *InstanceResource) waitForFlavor(ctx context.Context, region, id, flavorID string, timeout time.Duration) (string, error) {
+	var status string
+	var resizeErr error
+	err := i.client.WaitForCondition(ctx, timeout, func() (bool, error) {
+		det, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			return false, err
+		}
+		if det.Status == "ERROR" {
+			resizeErr = fmt.Errorf("instance status transitioned into invalid state ERROR")
+			return true, nil
+		}
+		if det.Flavor == nil || det.Flavor.ID != flavorID {
+			return false, nil
+		}
+		if det.Status != "ACTIVE" && det.Status != "SHUTOFF" {
+			return false, nil
+		}
+		status = det.Status
+		return true, nil
+	})
+	if err == nil {
+		err = resizeErr
+	}
+	return status, err
+}
+
+// warnInterruptedResize warns about a resize which was neither finished nor rolled back
+func warnInterruptedResize(data []byte) diag.Diagnostics {
+	var d diag.Diagnostics
+	var progress flavorResizeProgress
+	if len(data) == 0 || json.Unmarshal(data, &progress) != nil {
+		return d
+	}
+	d.AddWarning("interrupted flavor resize", fmt.Sprintf(
+		"resizing the instance from %s to %s stopped at step %s and could not be rolled back, flavor_id reflects the current flavor",
+		progress.FromFlavor, progress.ToFlavor, progress.Step))
+	return d
+}
@@ -13,18 +13,33 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+const (
+	powerStateRunning = "running"
+	powerStateStopped = "stopped"
+
+	rebootTypeSoft = "soft"
+	rebootTypeHard = "hard"
+
+	deleteModeForce    = "force"
+	deleteModeGraceful = "graceful"
+)
+
 type InstanceResource struct {
 	client *api.Client
 }
@@ -44,6 +59,7 @@ func (i *InstanceResource) Configure(ctx context.Context, req resource.Configure
 func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Blocks: map[string]schema.Block{
 			"timeouts": timeouts.Block(ctx, timeouts.Opts{
 				Create: true,
@@ -72,7 +88,44 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				Required: true,
 			},
 			"image_id": schema.StringAttribute{
-				Required: true,
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("disk_size")),
+				},
+			},
+			"delete_mode": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(deleteModeForce),
+				Validators: []validator.String{
+					stringvalidator.OneOf(deleteModeForce, deleteModeGraceful),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"rebuild_on_image_change": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"boot_volume_id": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("image_id")),
+					stringvalidator.ConflictsWith(path.MatchRoot("disk_size")),
+				},
+			},
+			"ha_enabled": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					// the api can not change ha on an existing instance
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
 			"enable_ipv4": schema.BoolAttribute{
 				Optional: true,
@@ -82,49 +135,42 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 			"enable_ipv6": schema.BoolAttribute{
 				Optional: true,
 			},
-			"networks": schema.ListNestedAttribute{
-				Optional: true,
+			"networks": schema.SetNestedAttribute{
+				Optional:   true,
+				CustomType: models.NetworkAttachmentSetType(),
+				PlanModifiers: []planmodifier.Set{
+					networksModifier{},
+				},
 				NestedObject: schema.NestedAttributeObject{
-					PlanModifiers: []planmodifier.Object{
-						misc.RemovePlanModifier{},
-					},
 					Attributes: map[string]schema.Attribute{
 						"subnet_id": schema.StringAttribute{
 							Computed: true,
 							Optional: true,
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"ip": schema.StringAttribute{
 							Optional: true,
 							Computed: true,
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"network_id": schema.StringAttribute{
 							Required: true,
 						},
 						"port_id": schema.StringAttribute{
 							Computed: true,
-							PlanModifiers: []planmodifier.String{
-								stringplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"is_public": schema.BoolAttribute{
 							Computed: true,
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
 						},
 						"port_security_enabled": schema.BoolAttribute{
 							Optional: true,
 							Default:  booldefault.StaticBool(true),
 							Computed: true,
-							PlanModifiers: []planmodifier.Bool{
-								boolplanmodifier.UseStateForUnknown(),
-							},
+						},
+						"ptr": schema.StringAttribute{
+							Computed: true,
+						},
+						"allowed_address_pairs": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
 						},
 					},
 				},
@@ -137,12 +183,21 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 				ElementType: types.StringType,
 			},
 			"disk_size": schema.Int64Attribute{
-				Required:   true,
+				Optional:   true,
 				Validators: []validator.Int64{int64validator.AtLeast(25)},
 			},
 			"init_script": schema.StringAttribute{
 				Optional: true,
 			},
+			"user_data": userDataSchema(),
+			"readiness": readinessSchema(),
+			"rescue":    rescueSchema(),
+			"rendered_user_data": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					renderedUserDataModifier{},
+				},
+			},
 			"volumes": schema.SetAttribute{
 				Optional: true,
 				//Computed:    true,
@@ -151,15 +206,71 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 			"ssh_key_name": schema.StringAttribute{
 				Optional: true,
 			},
+			"ssh_key_names": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Set{
+					setvalidator.SizeAtLeast(1),
+					setvalidator.ConflictsWith(path.MatchRoot("ssh_key_name")),
+				},
+			},
 			"password": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					rebuildPasswordModifier{},
+					passwordStorageModifier{},
+				},
+			},
+			"pgp_key": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					utl.PGPKeyValidator(),
+				},
+			},
+			"store_password": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"encrypted_password": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					rebuildPasswordModifier{},
+					passwordStorageModifier{encrypted: true},
 				},
 			},
 			"status": schema.StringAttribute{
 				Computed: true,
 			},
+			"power_state": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(powerStateRunning, powerStateStopped),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"tags": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"reboot_triggers": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"reboot_type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(rebootTypeSoft),
+				Validators: []validator.String{
+					stringvalidator.OneOf(rebootTypeSoft, rebootTypeHard),
+				},
+			},
 			"floating_ip": schema.SingleNestedAttribute{
 				Optional: true,
 				Attributes: map[string]schema.Attribute{
@@ -194,6 +305,14 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 			"dedicated_server_id": schema.StringAttribute{
 				Optional: true,
 			},
+			"migration_mode": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(migrationModeCold),
+				Validators: []validator.String{
+					stringvalidator.OneOf(migrationModeCold, migrationModeLive),
+				},
+			},
 			"cluster_id": schema.StringAttribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
@@ -206,6 +325,17 @@ func (i *InstanceResource) Schema(ctx context.Context, req resource.SchemaReques
 
 		},
 	}
+
+	for name, attribute := range addressAttributes() {
+		resp.Schema.Attributes[name] = attribute
+	}
+}
+
+func (i *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || i.client == nil {
+		return
+	}
+	i.validateSSHKeyNames(ctx, req, resp)
 }
 
 func (i *InstanceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -222,14 +352,23 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
+	rescue, d := data.GetRescue(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if rescueEnabled(rescue) && data.PowerState.ValueString() == powerStateStopped {
+		resp.Diagnostics.AddAttributeError(path.Root("rescue"), "invalid rescue mode", "rescue mode requires power_state to be running")
+		return
+	}
+
 	apiCreateReq := api.InstanceCreateRequest{
 		Name:              data.Name.ValueString(),
 		Count:             1,
 		ImageID:           data.ImageID.ValueString(),
 		FlavorID:          data.FlavorID.ValueString(),
-		SSHKey:            !data.SSHKeyName.IsNull(),
 		DiskSize:          int(data.DiskSize.ValueInt64()),
-		OSVolumeID:        "",
+		OSVolumeID:        data.BootVolumeID.ValueString(),
 		InitScript:        data.InitScript.ValueString(),
 		DedicatedServerID: data.DedicatedServerID.ValueString(),
 		EnableIPv4:        data.EnableIPv4.ValueBool(),
@@ -251,6 +390,26 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		apiCreateReq.EnableIPv6 = data.EnableIPv6.ValueBool()
 	}
 
+	if !data.HAEnabled.IsNull() && !data.HAEnabled.IsUnknown() {
+		haEnabled := data.HAEnabled.ValueBool()
+		apiCreateReq.HAEnabled = &haEnabled
+	}
+
+	// rendered_user_data already contains the init script when both are set
+	rendered, d := renderUserData(ctx, data.UserData, data.InitScript)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	data.RenderedUserData = rendered
+	if !rendered.IsNull() {
+		apiCreateReq.InitScript = rendered.ValueString()
+	}
+	if err := utl.CheckUserDataSize(apiCreateReq.InitScript); err != nil {
+		resp.Diagnostics.AddError("invalid init script", err.Error())
+		return
+	}
+
 	tfNets, d := data.GetNetworkAttachments(ctx)
 	resp.Diagnostics.Append(d...)
 	if resp.Diagnostics.HasError() {
@@ -260,8 +419,10 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		apiCreateReq.NetworkIDs = append(apiCreateReq.NetworkIDs, n.NetworkID.ValueString())
 	}
 
-	if apiCreateReq.SSHKey {
-		apiCreateReq.KeyName = data.SSHKeyName.ValueString()
+	apiCreateReq.KeyName, apiCreateReq.SSHKey, d = sshKeyName(ctx, &data)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
 	sgs, d := data.GetSecurityGroups(ctx)
@@ -291,9 +452,18 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	}
 	data.ID = types.StringValue(apiResp.Data.ID)
 	data.TaskID = types.StringValue(apiResp.Data.TaskID)
-	data.Password = types.StringValue(apiResp.Data.Password)
+	resp.Diagnostics.Append(setInstancePassword(&data, apiResp.Data.Password)...)
 	data.Status = types.StringValue(apiResp.Data.Status)
 
+	// the instance exists from here on, it is saved right away so a later failure leaves it
+	// tainted in state instead of leaking it
+	if data.ID.ValueString() != "" {
+		resp.Diagnostics.Append(misc.SetPartialState(ctx, &resp.State, &data)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	err = i.client.WaitForCondition(ctx, createTimeout, func() (bool, error) {
 		var detail *api.ServerDetail
 		var err error
@@ -311,6 +481,9 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 		tflog.Info(ctx, "STATUS", map[string]interface{}{"STATUS": detail.Status})
 		if detail.Status == "ACTIVE" {
 			data.Status = types.StringValue(detail.Status)
+			if data.HAEnabled.IsUnknown() {
+				data.HAEnabled = types.BoolValue(detail.HAEnabled)
+			}
 			return true, nil
 		}
 
@@ -327,6 +500,9 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	if err != nil {
 		tflog.Info(ctx, "STATUS", map[string]interface{}{"err": err})
 		resp.Diagnostics.AddError("instance power on error", err.Error())
+		if data.ID.ValueString() != "" {
+			resp.Diagnostics.Append(misc.SetPartialState(ctx, &resp.State, &data)...)
+		}
 		return
 	}
 
@@ -364,6 +540,7 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 				tfNets[idx].SubnetID = types.StringValue(a.SubnetID)
 				tfNets[idx].IsPublic = types.BoolValue(a.IsPublic)
 				tfNets[idx].PortSecurityEnabled = types.BoolValue(a.PortSecurityEnabled)
+				tfNets[idx].PTR = types.StringValue(a.PTR)
 
 			}
 
@@ -381,6 +558,20 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
+	resp.Diagnostics.Append(misc.SetPartialState(ctx, &resp.State, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, n := range tfNets {
+		if n.AllowedAddressPairs.IsNull() {
+			continue
+		}
+		resp.Diagnostics.Append(i.setAllowedAddressPairs(ctx, data.Region.ValueString(), n)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
 	volIds, d := data.GetVolumes(ctx)
 	resp.Diagnostics.Append(d...)
@@ -400,6 +591,19 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 
 	}
 
+	tags, d := data.GetTags(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	for _, t := range tags {
+		err = i.client.Tag.AttachTag(ctx, data.Region.ValueString(), t, data.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("error attaching tag", err.Error())
+			return
+		}
+	}
+
 	if !data.FloatingIP.IsNull() {
 		att, d := data.GetFloatingIPAttachment(ctx)
 		resp.Diagnostics.Append(d...)
@@ -434,8 +638,46 @@ func (i *InstanceResource) Create(ctx context.Context, req resource.CreateReques
 			}
 		}
 	}
+
+	if data.PowerState.ValueString() == powerStateStopped {
+		status, err := i.setPowerState(ctx, data.Region.ValueString(), data.ID.ValueString(), powerStateStopped, createTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError("error powering off instance", err.Error())
+			return
+		}
+		data.Status = types.StringValue(status)
+	}
+	if rescueEnabled(rescue) {
+		err = i.enterRescue(ctx, data.Region.ValueString(), data.ID.ValueString(), rescue, createTimeout)
+		if err != nil {
+			resp.Diagnostics.AddError("error entering rescue mode", err.Error())
+			return
+		}
+		data.Status = types.StringValue(statusRescue)
+	}
+	data.PowerState = types.StringValue(powerStateFromStatus(data.Status.ValueString(), powerStateRunning))
+
+	resp.Diagnostics.Append(i.refreshAddressView(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	if resp.Diagnostics.HasError() || data.Readiness.IsNull() {
+		return
+	}
 
+	if rescueEnabled(rescue) {
+		resp.Diagnostics.AddWarning("readiness checks skipped", "readiness checks are not run for instances in rescue mode")
+		return
+	}
+
+	// the instance is saved before the checks, a failing check taints it
+	if data.PowerState.ValueString() == powerStateStopped {
+		resp.Diagnostics.AddWarning("readiness checks skipped", "readiness checks are not run for stopped instances")
+		return
+	}
+	resp.Diagnostics.Append(i.waitForReadiness(ctx, &data, tfNets)...)
 }
 
 func (i *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -458,20 +700,43 @@ func (i *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 		return
 	}
 	data.Status = types.StringValue(apiResp.Status)
+	data.PowerState = types.StringValue(powerStateFromStatus(apiResp.Status, data.PowerState.ValueString()))
 	data.Name = types.StringValue(apiResp.Name)
 	data.ClusterID = types.StringValue(apiResp.ClusterID)
+	data.HAEnabled = types.BoolValue(apiResp.HAEnabled)
 
 	if apiResp.DedicatedServerID != "" {
 		data.DedicatedServerID = types.StringValue(apiResp.DedicatedServerID)
 	}
 
-	if apiResp.Image != nil {
+	// instances booted from a volume report the image of the volume
+	if apiResp.Image != nil && data.BootVolumeID.IsNull() {
 		data.ImageID = types.StringValue(apiResp.Image.ID)
 	}
 	if apiResp.Flavor != nil {
 		data.FlavorID = types.StringValue(apiResp.Flavor.ID)
 	}
+
+	resize, d := req.Private.GetKey(ctx, flavorResizePrivateKey)
+	resp.Diagnostics.Append(d...)
+	resp.Diagnostics.Append(warnInterruptedResize(resize)...)
+
+	rescue, d := data.GetRescue(ctx)
+	resp.Diagnostics.Append(d...)
+	resp.Diagnostics.Append(data.SetRescue(ctx, rescueFromStatus(rescue, apiResp.Status))...)
 	
+	// tags are only tracked when managed, otherwise tags set outside of terraform would be removed
+	if !data.Tags.IsNull() {
+		var tags []string
+		for _, t := range apiResp.Tags {
+			tags = append(tags, t.Name)
+		}
+		resp.Diagnostics.Append(data.SetTags(ctx, tags)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	if len(apiResp.SecurityGroups) > 0 {
 		var sgIds = make(map[string]bool)
 		for _, sg := range apiResp.SecurityGroups {
@@ -499,6 +764,8 @@ func (i *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 			PortID:              types.StringValue(a.PortID),
 			IsPublic:            types.BoolValue(a.IsPublic),
 			PortSecurityEnabled: types.BoolValue(a.PortSecurityEnabled),
+			PTR:                 types.StringValue(a.PTR),
+			AllowedAddressPairs: types.SetNull(types.StringType),
 		}
 		/*if !a.PortSecurityEnabled {
 			tfX.PortSecurityEnabled = types.BoolNull()
@@ -532,13 +799,31 @@ func (i *InstanceResource) Read(ctx context.Context, req resource.ReadRequest, r
 	}
 	existing = append(existing, toAdd...)
 
+	// ptr records are managed outside of the instance, always refresh them
+	for idx := range oldAttachments {
+		if a, ok := attachments[oldAttachments[idx].NetworkID.ValueString()]; ok {
+			oldAttachments[idx].PTR = types.StringValue(a.PTR)
+		}
+	}
+
+	resp.Diagnostics.Append(i.refreshAllowedAddressPairs(ctx, data.Region.ValueString(), oldAttachments)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	d = data.SetNetworkAttachments(ctx, oldAttachments)
 	resp.Diagnostics.Append(d...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	serverVolumes, err := i.client.Volume.GetServerVolumes(ctx, data.Region.ValueString(), data.ID.ValueString())
+	resp.Diagnostics.Append(setAddressView(ctx, &data, newAddressView(apiResp.Addresses, attachments, networkIDs(oldAttachments)))...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// the boot disk is attached to the server too but is not managed through volumes
+	serverVolumes, err := i.client.Volume.GetServerDataVolumes(ctx, data.Region.ValueString(), data.ID.ValueString(), data.BootVolumeID.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("error fetching server volumes", err.Error())
 		return
@@ -582,7 +867,21 @@ func (i *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	err := i.client.Instance.DeleteInstance(ctx, data.Region.ValueString(), data.ID.ValueString())
+
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("deletion protection", "instance has deletion_protection enabled, disable it before destroying the instance")
+		return
+	}
+
+	deleteTimeout, d := data.Timeouts.Delete(ctx, time.Minute*2)
+	resp.Diagnostics.Append(d...)
+
+	var err error
+	if data.DeleteMode.ValueString() == deleteModeGraceful {
+		err = i.gracefulDelete(ctx, &data, deleteTimeout)
+	} else {
+		err = i.client.Instance.DeleteInstance(ctx, data.Region.ValueString(), data.ID.ValueString())
+	}
 	if err != nil {
 		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
 			return
@@ -591,9 +890,6 @@ func (i *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 		return
 	}
 
-	deleteTimeout, d := data.Timeouts.Delete(ctx, time.Minute*2)
-	resp.Diagnostics.Append(d...)
-
 	err = i.client.WaitForCondition(ctx, deleteTimeout, func() (bool, error) {
 		_, err := i.client.Instance.GetInstance(ctx, data.Region.ValueString(), data.ID.ValueString())
 		if err != nil && err.(*api.ResponseError).Code == 404 {
@@ -611,6 +907,63 @@ func (i *InstanceResource) Delete(ctx context.Context, req resource.DeleteReques
 	}
 }
 
+// gracefulDelete powers the instance off, releases its data volumes, floating ip and
+// security groups and then deletes it without forcing
+func (i *InstanceResource) gracefulDelete(ctx context.Context, data *models.TFInstanceResourceModel, timeout time.Duration) error {
+	region, id := data.Region.ValueString(), data.ID.ValueString()
+
+	if _, err := i.setPowerState(ctx, region, id, powerStateStopped, timeout); err != nil {
+		return err
+	}
+
+	// only data volumes are detached, the boot volume can not leave its instance
+	volumes, err := i.client.Volume.GetServerDataVolumes(ctx, region, id, data.BootVolumeID.ValueString())
+	if err != nil {
+		return err
+	}
+	for _, v := range volumes {
+		err = i.client.Volume.DetachVolume(ctx, region, &api.VolumeAttachDetach{
+			ServerID: id,
+			VolumeID: v,
+		})
+		if err != nil {
+			return fmt.Errorf("error detaching volume %s: %w", v, err)
+		}
+	}
+
+	fipInfo, err := i.client.GetServerFloatingIPInfo(ctx, region, id)
+	if err != nil {
+		if respErr, ok := err.(*api.ResponseError); !ok || respErr.Code != 404 {
+			return err
+		}
+	} else {
+		nets, d := data.GetNetworkAttachments(ctx)
+		if d.HasError() {
+			return errors.New("failed to read network attachments")
+		}
+		for _, n := range nets {
+			if n.NetworkID.ValueString() != fipInfo.PrivateNetworkID {
+				continue
+			}
+			if err := i.client.FIPClient.DetachFloatingIP(ctx, region, n.PortID.ValueString()); err != nil {
+				return fmt.Errorf("error detaching floating ip: %w", err)
+			}
+		}
+	}
+
+	detail, err := i.client.Instance.GetInstance(ctx, region, id)
+	if err != nil {
+		return err
+	}
+	for _, sg := range detail.SecurityGroups {
+		if err := i.client.Firewall.RemoveServerFromGroup(ctx, region, id, sg.ID); err != nil {
+			return fmt.Errorf("error removing server from security group %s: %w", sg.ID, err)
+		}
+	}
+
+	return i.client.Instance.GracefulDeleteInstance(ctx, region, id)
+}
+
 func (i *InstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	tflog.Info(ctx, "UPDATING")
 	var planData models.TFInstanceResourceModel
@@ -635,12 +988,30 @@ func (i *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	i.handleRebuild(ctx, &stateData, &planData, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	i.handlePasswordStorage(ctx, &stateData, &planData, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	i.handleRename(ctx, &stateData, &planData, resp)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	i.handleTags(ctx, &stateData, &planData, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	i.handleSecurityGroups(ctx, &stateData, &planData, resp)
 
 	if resp.Diagnostics.HasError() {
@@ -653,6 +1024,12 @@ func (i *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	i.handleMigration(ctx, &stateData, &planData, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	i.handleVolumeAttachments(ctx, &stateData, &planData, resp)
 
 	if resp.Diagnostics.HasError() {
@@ -665,6 +1042,12 @@ func (i *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	i.handlePublicIPs(ctx, &stateData, &planData, resp)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	i.handleRootVolumeResize(ctx, &stateData, &planData, resp)
 
 	if resp.Diagnostics.HasError() {
@@ -683,6 +1066,28 @@ func (i *InstanceResource) Update(ctx context.Context, req resource.UpdateReques
 		return
 	}
 
+	i.handleRebootTriggers(ctx, &stateData, &planData, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	i.handleRescue(ctx, &stateData, &planData, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	i.handlePowerState(ctx, &stateData, &planData, resp)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if addressesChanged(&stateData, &planData) {
+		resp.Diagnostics.Append(i.refreshAddressView(ctx, &planData)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
 	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
 
 }
@@ -712,40 +1117,38 @@ func (i *InstanceResource) handleSimpleChanges(ctx context.Context, stateData, p
 		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("status"), planData.Status.ValueString())...)
 	}
 
-	if !planData.ImageID.Equal(stateData.ImageID) {
-		resp.Diagnostics.AddError(unsupportedOperation, "image id can not be changed")
+	rebuild := rebuildRequested(stateData, planData)
+	if !planData.ImageID.Equal(stateData.ImageID) && !rebuild {
+		resp.Diagnostics.AddError(unsupportedOperation, "image id can not be changed unless rebuild_on_image_change is set")
+	}
+
+	if !planData.BootVolumeID.Equal(stateData.BootVolumeID) {
+		resp.Diagnostics.AddError(unsupportedOperation, "boot volume id can only be set at creation time")
 	}
 
 	if strings.HasPrefix(stateData.FlavorID.ValueString(), "ls") && !planData.FlavorID.Equal(stateData.FlavorID) {
 		resp.Diagnostics.AddError(unsupportedOperation, "instances with local storage can not be resized")
 	}
 
-	if !planData.InitScript.Equal(stateData.InitScript) {
+	// init script and user data run again on rebuild
+	if !planData.InitScript.Equal(stateData.InitScript) && !rebuild {
 		resp.Diagnostics.AddError(unsupportedOperation, "init script can only be set at creation time")
 	}
 
-	if !planData.SSHKeyName.Equal(stateData.SSHKeyName) {
-		resp.Diagnostics.AddError(unsupportedOperation, "ssh key name can only be set at creation time")
-	}
-
-	if !planData.Region.Equal(stateData.Region) {
-		resp.Diagnostics.AddError(unsupportedOperation, "region can only be set at creation time")
-	}
-
-	if !planData.ServerGroupID.Equal(stateData.ServerGroupID) {
-		resp.Diagnostics.AddError(unsupportedOperation, "server group id can only be set at creation time")
+	if !planData.UserData.Equal(stateData.UserData) && !rebuild {
+		resp.Diagnostics.AddError(unsupportedOperation, "user data can only be set at creation time")
 	}
 
-	if !planData.DedicatedServerID.IsNull() && !planData.DedicatedServerID.Equal(stateData.DedicatedServerID) {
-		resp.Diagnostics.AddError(unsupportedOperation, "dedicated server id can only be set at creation time")
+	if !planData.SSHKeyName.Equal(stateData.SSHKeyName) {
+		resp.Diagnostics.AddError(unsupportedOperation, "ssh key name can only be set at creation time")
 	}
 
-	if !planData.EnableIPv4.Equal(stateData.EnableIPv4) {
-		resp.Diagnostics.AddError(unsupportedOperation, "setting public IPs can only happen at creation time")
+	if !planData.SSHKeyNames.Equal(stateData.SSHKeyNames) {
+		resp.Diagnostics.AddError(unsupportedOperation, "ssh key names can only be set at creation time")
 	}
 
-	if !planData.EnableIPv6.IsNull() && !planData.EnableIPv6.Equal(stateData.EnableIPv6) {
-		resp.Diagnostics.AddError(unsupportedOperation, "setting public IPs can only happen at creation time")
+	if !planData.Region.Equal(stateData.Region) {
+		resp.Diagnostics.AddError(unsupportedOperation, "region can only be set at creation time")
 	}
 
 	return
@@ -761,71 +1164,48 @@ func (i *InstanceResource) handleRename(ctx context.Context, stateData, planData
 	}
 }
 
-func (i *InstanceResource) handleFlavorResize(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
-	if planData.FlavorID.Equal(stateData.FlavorID) {
-		return
-	}
-
-	resp.Diagnostics.AddWarning("instance power off", "during resize operation your instance powers off")
-	err := i.client.Instance.PowerOffInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("error powering off instance", err.Error())
+func (i *InstanceResource) handleTags(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	if planData.Tags.Equal(stateData.Tags) {
 		return
 	}
 
-	updateTimeout, d := planData.Timeouts.Update(ctx, 5*time.Minute)
+	stateTags, d := stateData.GetTags(ctx)
 	resp.Diagnostics.Append(d...)
-
-	err = i.client.WaitForCondition(ctx, updateTimeout, func() (bool, error) {
-		det, err := i.client.Instance.GetInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-		if err != nil {
-			return false, err
-		}
-		if det.Status == "SHUTOFF" {
-			planData.Status = types.StringValue(det.Status)
-			return true, nil
-		}
-		return false, nil
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("error powering off instance", err.Error())
+	planTags, d := planData.GetTags(ctx)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	err = i.client.Instance.ResizeInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), planData.FlavorID.String())
+	det, err := i.client.Instance.GetInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("error resizing instance", err.Error())
+		resp.Diagnostics.AddError("error fetching instance", err.Error())
 		return
 	}
-
-	err = i.client.Instance.PowerOnInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-	if err != nil {
-		resp.Diagnostics.AddError("error powering on instance", err.Error())
-		return
+	tagIDs := make(map[string]string)
+	for _, t := range det.Tags {
+		tagIDs[t.Name] = t.ID
 	}
 
-	err = i.client.WaitForCondition(ctx, updateTimeout, func() (bool, error) {
-		det, err := i.client.Instance.GetInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-		if err != nil {
-			return false, err
+	for _, w := range utl.GetWhatToDo(utl.ListGoStringToSet(planTags), utl.ListGoStringToSet(stateTags)) {
+		if w.Do {
+			err := i.client.Tag.AttachTag(ctx, stateData.Region.ValueString(), w.ID, stateData.ID.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError("error attaching tag", err.Error())
+				return
+			}
+			continue
 		}
-		if det.Status == "ACTIVE" {
-			planData.Status = types.StringValue(det.Status)
-			return true, nil
+		tagID, ok := tagIDs[w.ID]
+		if !ok {
+			continue
 		}
-
-		if det.Status == "ERROR" {
-			planData.Status = types.StringValue(det.Status)
-			return false, errors.New("instance state transitioned to ERROR")
+		err := i.client.Tag.DetachTag(ctx, stateData.Region.ValueString(), tagID, stateData.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("error detaching tag", err.Error())
+			return
 		}
-
-		return false, nil
-	})
-	if err != nil {
-		resp.Diagnostics.AddError("instance power on failed", err.Error())
-		return
 	}
-
 }
 
 func (i *InstanceResource) handleVolumeAttachments(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
@@ -909,8 +1289,20 @@ func (i *InstanceResource) handleNetworkAttachments(ctx context.Context, stateDa
 			continue
 		}
 
-		if currentAttachment, _ := stateData.GetNetworkAttachment(ctx, planNet.NetworkID.ValueString()); currentAttachment != nil {
-			tflog.Warn(ctx, fmt.Sprintf("network %s exists but needs changes, detaching", planNet.NetworkID.ValueString()))
+		currentAttachment, _ := stateData.GetNetworkAttachment(ctx, planNet.NetworkID.ValueString())
+		if currentAttachment != nil {
+			updated, inPlace, d := i.updateNetworkAttachment(ctx, stateData.Region.ValueString(), *currentAttachment, planNet)
+			resp.Diagnostics.Append(d...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			if inPlace {
+				tflog.Warn(ctx, fmt.Sprintf("network %s updated in place", planNet.NetworkID.ValueString()))
+				newNetStates = append(newNetStates, updated)
+				continue
+			}
+
+			tflog.Warn(ctx, fmt.Sprintf("network %s exists but needs a new port, detaching", planNet.NetworkID.ValueString()))
 
 			tflog.Warn(ctx, "NETWORK_DETACH", map[string]interface{}{"NETWORK_ID": planNet.NetworkID.ValueString()})
 			err := i.client.Subnet.DetachServerFromNetwork(ctx, stateData.Region.ValueString(), currentAttachment.PortID.ValueString(), stateData.ID.ValueString())
@@ -940,14 +1332,23 @@ func (i *InstanceResource) handleNetworkAttachments(ctx context.Context, stateDa
 			return
 		}
 
-		newNetStates = append(newNetStates, models.TFNetworkAttachment{
+		newNet := models.TFNetworkAttachment{
 			PortID:              types.StringValue(apiResp.ID),
 			IP:                  types.StringValue(apiResp.IPAddress),
 			SubnetID:            types.StringValue(apiResp.SubnetID),
 			NetworkID:           types.StringValue(apiResp.NetworkID),
 			PortSecurityEnabled: planNet.PortSecurityEnabled,
 			IsPublic:            types.BoolValue(false),
-		})
+			PTR:                 types.StringValue(""),
+			AllowedAddressPairs: planNet.AllowedAddressPairs,
+		}
+		if !newNet.AllowedAddressPairs.IsNull() {
+			resp.Diagnostics.Append(i.setAllowedAddressPairs(ctx, stateData.Region.ValueString(), newNet)...)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+		}
+		newNetStates = append(newNetStates, newNet)
 	}
 
 	// keep the networks null value instead of empty list
@@ -956,6 +1357,62 @@ func (i *InstanceResource) handleNetworkAttachments(ctx context.Context, stateDa
 	}
 }
 
+func (i *InstanceResource) setAllowedAddressPairs(ctx context.Context, region string, net models.TFNetworkAttachment) diag.Diagnostics {
+	var diags diag.Diagnostics
+	addrs, d := net.GetAllowedAddressPairs(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return diags
+	}
+
+	var pairs []api.AllowedAddressPair
+	for _, a := range addrs {
+		pairs = append(pairs, api.AllowedAddressPair{IPAddress: a})
+	}
+
+	err := i.client.Subnet.SetAllowedAddressPairs(ctx, region, net.PortID.ValueString(), pairs)
+	if err != nil {
+		diags.AddError("error setting allowed address pairs", err.Error())
+	}
+	return diags
+}
+
+// addressPairsFromPort returns the allowed address pairs of port, pairs are only tracked when
+// managed so a null current value stays null
+func addressPairsFromPort(ctx context.Context, current types.Set, port *api.AttachedPort) (types.Set, diag.Diagnostics) {
+	if current.IsNull() {
+		return current, nil
+	}
+	ips := []string{}
+	for _, p := range port.AllowedAddressPairs {
+		ips = append(ips, p.IPAddress)
+	}
+	return types.SetValueFrom(ctx, types.StringType, ips)
+}
+
+// refreshAllowedAddressPairs reads the allowed address pairs of the managed attachments back
+// from their ports so pairs removed outside of terraform show up in the plan
+func (i *InstanceResource) refreshAllowedAddressPairs(ctx context.Context, region string, nets []models.TFNetworkAttachment) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for idx := range nets {
+		if nets[idx].AllowedAddressPairs.IsNull() || nets[idx].PortID.ValueString() == "" {
+			continue
+		}
+		port, err := i.client.Subnet.GetPort(ctx, region, nets[idx].PortID.ValueString())
+		if err != nil {
+			diags.AddError("error fetching port", err.Error())
+			return diags
+		}
+		pairs, d := addressPairsFromPort(ctx, nets[idx].AllowedAddressPairs, port)
+		diags.Append(d...)
+		if diags.HasError() {
+			return diags
+		}
+		nets[idx].AllowedAddressPairs = pairs
+	}
+	return diags
+}
+
 func (i *InstanceResource) handleRootVolumeResize(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
 	if !planData.DiskSize.Equal(stateData.DiskSize) {
 		if strings.HasPrefix(planData.FlavorID.ValueString(), "ls") {
@@ -967,34 +1424,18 @@ func (i *InstanceResource) handleRootVolumeResize(ctx context.Context, stateData
 			return
 		}
 
-		if stateData.Status.ValueString() == "ACTIVE" {
-			resp.Diagnostics.AddWarning("instance power off", "during root volume resize operation your instance powers off")
-			err := i.client.Instance.PowerOffInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-			if err != nil {
-				resp.Diagnostics.AddError("error powering off instance", err.Error())
-				return
-			}
-		}
-
 		updateTimeout, d := planData.Timeouts.Update(ctx, 5*time.Minute)
 		resp.Diagnostics.Append(d...)
 
-		err := i.client.WaitForCondition(ctx, updateTimeout, func() (bool, error) {
-			det, err := i.client.Instance.GetInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
-			if err != nil {
-				return false, err
-			}
-			if det.Status == "SHUTOFF" {
-				planData.Status = types.StringValue(det.Status)
-				return true, nil
-			}
-
-			return false, nil
-		})
+		if stateData.Status.ValueString() == "ACTIVE" {
+			resp.Diagnostics.AddWarning("instance power off", "during root volume resize operation your instance powers off")
+		}
+		status, err := i.setPowerState(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), powerStateStopped, updateTimeout)
 		if err != nil {
 			resp.Diagnostics.AddError("error powering off instance", err.Error())
 			return
 		}
+		planData.Status = types.StringValue(status)
 
 		err = i.client.Instance.ResizeRootVolume(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), planData.DiskSize.ValueInt64())
 		if err != nil {
@@ -1020,6 +1461,15 @@ func (i *InstanceResource) handleRootVolumeResize(ctx context.Context, stateData
 			return
 		}
 
+		// root volume resize always boots the instance, put it back to the desired power state
+		if desiredPowerState(stateData, planData) == powerStateStopped {
+			status, err = i.setPowerState(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), powerStateStopped, updateTimeout)
+			if err != nil {
+				resp.Diagnostics.AddError("error powering off instance", err.Error())
+				return
+			}
+			planData.Status = types.StringValue(status)
+		}
 	}
 
 }
@@ -1059,6 +1509,26 @@ func (i *InstanceResource) handleSecurityGroups(ctx context.Context, stateData,
 	}
 }
 
+// detachFloatingIP detaches the floating ip in state from the port of its network
+func (i *InstanceResource) detachFloatingIP(ctx context.Context, stateData *models.TFInstanceResourceModel, nets []models.TFNetworkAttachment) diag.Diagnostics {
+	stateFip, diags := stateData.GetFloatingIPAttachment(ctx)
+	if diags.HasError() {
+		return diags
+	}
+	var portID string
+	for _, n := range nets {
+		if n.NetworkID.Equal(stateFip.NetworkID) {
+			portID = n.PortID.ValueString()
+			break
+		}
+	}
+
+	if err := i.client.FIPClient.DetachFloatingIP(ctx, stateData.Region.ValueString(), portID); err != nil {
+		diags.AddError("error detaching floating ip", err.Error())
+	}
+	return diags
+}
+
 func (i *InstanceResource) handleFloatingIP(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
 	if planData.FloatingIP.Equal(stateData.FloatingIP) {
 		return
@@ -1071,24 +1541,10 @@ func (i *InstanceResource) handleFloatingIP(ctx context.Context, stateData, plan
 	}
 
 	if !stateData.FloatingIP.IsNull() {
-		var portID string
-		stateFip, d := stateData.GetFloatingIPAttachment(ctx)
-		resp.Diagnostics.Append(d...)
+		resp.Diagnostics.Append(i.detachFloatingIP(ctx, stateData, tfStateNets)...)
 		if resp.Diagnostics.HasError() {
 			return
 		}
-		for _, n := range tfStateNets {
-			if n.NetworkID.Equal(stateFip.NetworkID) {
-				portID = n.PortID.ValueString()
-			}
-			break
-		}
-
-		err := i.client.FIPClient.DetachFloatingIP(ctx, stateData.Region.ValueString(), portID)
-		if err != nil {
-			resp.Diagnostics.AddError("error detaching floating ip", err.Error())
-			return
-		}
 	}
 
 	if !planData.FloatingIP.IsNull() {
@@ -1139,6 +1595,188 @@ func (i *InstanceResource) handleFloatingIP(ctx context.Context, stateData, plan
 
 }
 
+// rebootForTriggers returns the reboot type to run for a reboot_triggers change, empty when
+// there is nothing to reboot for. stopped reports a change skipped since the instance is off
+func rebootForTriggers(stateData, planData *models.TFInstanceResourceModel) (rebootType string, stopped bool) {
+	// adding triggers to an existing instance only records them, there is nothing to reboot for yet
+	if planData.RebootTriggers.Equal(stateData.RebootTriggers) || stateData.RebootTriggers.IsNull() || planData.RebootTriggers.IsNull() {
+		return "", false
+	}
+	if planData.Status.ValueString() == "SHUTOFF" || desiredPowerState(stateData, planData) == powerStateStopped {
+		return "", true
+	}
+	if planData.RebootType.ValueString() == rebootTypeHard {
+		return rebootTypeHard, false
+	}
+	return rebootTypeSoft, false
+}
+
+func (i *InstanceResource) handleRebootTriggers(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	rebootType, stopped := rebootForTriggers(stateData, planData)
+	if stopped {
+		resp.Diagnostics.AddWarning("reboot skipped", "reboot triggers changed but the instance is stopped")
+		return
+	}
+	if rebootType == "" {
+		return
+	}
+
+	var err error
+	if rebootType == rebootTypeHard {
+		err = i.client.Instance.HardRebootInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
+	} else {
+		err = i.client.Instance.RebootInstance(ctx, stateData.Region.ValueString(), stateData.ID.ValueString())
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("error rebooting instance", err.Error())
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(d...)
+
+	err = i.waitForStatus(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), "ACTIVE", updateTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError("error rebooting instance", err.Error())
+		return
+	}
+	planData.Status = types.StringValue("ACTIVE")
+}
+
+func (i *InstanceResource) handlePowerState(ctx context.Context, stateData, planData *models.TFInstanceResourceModel, resp *resource.UpdateResponse) {
+	powerState := desiredPowerState(stateData, planData)
+	planData.PowerState = types.StringValue(powerState)
+	if powerStateFromStatus(planData.Status.ValueString(), "") == powerState {
+		return
+	}
+
+	updateTimeout, d := planData.Timeouts.Update(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(d...)
+
+	status, err := i.setPowerState(ctx, stateData.Region.ValueString(), stateData.ID.ValueString(), powerState, updateTimeout)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("error changing instance power state to %s", powerState), err.Error())
+		return
+	}
+	planData.Status = types.StringValue(status)
+}
+
+// setPowerState powers the instance on or off and waits for it to settle, it does
+// nothing if the instance is already in the requested power state
+func (i *InstanceResource) setPowerState(ctx context.Context, region, id, powerState string, timeout time.Duration) (string, error) {
+	target, action := "ACTIVE", i.client.Instance.PowerOnInstance
+	if powerState == powerStateStopped {
+		target, action = "SHUTOFF", i.client.Instance.PowerOffInstance
+	}
+
+	det, err := i.client.Instance.GetInstance(ctx, region, id)
+	if err != nil {
+		return "", err
+	}
+	if det.Status == target {
+		return target, nil
+	}
+
+	err = action(ctx, region, id)
+	if err != nil {
+		return det.Status, err
+	}
+
+	err = i.waitForStatus(ctx, region, id, target, timeout)
+	if err != nil {
+		return det.Status, err
+	}
+	return target, nil
+}
+
+func (i *InstanceResource) waitForStatus(ctx context.Context, region, id, status string, timeout time.Duration) error {
+	return i.client.WaitForCondition(ctx, timeout, func() (bool, error) {
+		det, err := i.client.Instance.GetInstance(ctx, region, id)
+		if err != nil {
+			return false, err
+		}
+		if det.Status == "ERROR" && status != "ERROR" {
+			return false, errors.New("instance state transitioned to ERROR")
+		}
+		return det.Status == status, nil
+	})
+}
+
+// desiredPowerState returns the configured power state, falling back to whatever
+// the instance had before when it is not set
+func desiredPowerState(stateData, planData *models.TFInstanceResourceModel) string {
+	if !planData.PowerState.IsNull() && !planData.PowerState.IsUnknown() {
+		return planData.PowerState.ValueString()
+	}
+	return powerStateFromStatus(stateData.Status.ValueString(), powerStateRunning)
+}
+
+// powerStateFromStatus maps instance status to power state, transitional statuses
+// (e.g. REBOOT, RESIZE) keep the fallback value
+func powerStateFromStatus(status, fallback string) string {
+	switch status {
+	case "ACTIVE", statusRescue:
+		return powerStateRunning
+	case "SHUTOFF":
+		return powerStateStopped
+	}
+	return fallback
+}
+
+func (i *InstanceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	i.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	return map[int64]resource.StateUpgrader{
+		0: misc.NewJSONStateUpgrader(schemaResp.Schema, upgradeInstanceStateV0),
+	}
+}
+
+// upgradeInstanceStateV0 fixes up networks saved as plain network ids or without the
+// is_public/port_security_enabled flags and fills the attributes added since
+func upgradeInstanceStateV0(state map[string]interface{}) {
+	if networks, ok := state["networks"].([]interface{}); ok {
+		for idx, item := range networks {
+			network, ok := item.(map[string]interface{})
+			if !ok {
+				networkID, _ := item.(string)
+				network = map[string]interface{}{"network_id": networkID}
+			}
+			if network["is_public"] == nil {
+				network["is_public"] = false
+			}
+			if network["port_security_enabled"] == nil {
+				network["port_security_enabled"] = true
+			}
+			networks[idx] = network
+		}
+	}
+
+	if state["power_state"] == nil {
+		status, _ := state["status"].(string)
+		state["power_state"] = powerStateFromStatus(status, powerStateRunning)
+	}
+	if state["reboot_type"] == nil {
+		state["reboot_type"] = rebootTypeSoft
+	}
+	// attributes with a default would otherwise plan an update on the first apply
+	if state["rebuild_on_image_change"] == nil {
+		state["rebuild_on_image_change"] = false
+	}
+	if state["delete_mode"] == nil {
+		state["delete_mode"] = deleteModeForce
+	}
+	if state["deletion_protection"] == nil {
+		state["deletion_protection"] = false
+	}
+	if state["store_password"] == nil {
+		state["store_password"] = true
+	}
+	if state["migration_mode"] == nil {
+		state["migration_mode"] = migrationModeCold
+	}
+}
+
 func NewInstanceResource() resource.Resource {
 	return &InstanceResource{}
 }
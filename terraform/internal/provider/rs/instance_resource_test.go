@@ -0,0 +1,126 @@
+package rs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+func TestAddressPairsFromPort(t *testing.T) {
+	ctx := context.Background()
+	port := &api.AttachedPort{AllowedAddressPairs: []api.AllowedAddressPair{{IPAddress: "10.0.0.100"}}}
+
+	got, _ := addressPairsFromPort(ctx, types.SetNull(types.StringType), port)
+	if !got.IsNull() {
+		t.Errorf("expected unmanaged pairs to stay null, got %s", got)
+	}
+
+	managed, _ := types.SetValueFrom(ctx, types.StringType, []string{"10.0.0.100", "10.0.0.101"})
+	got, _ = addressPairsFromPort(ctx, managed, port)
+	expected, _ := types.SetValueFrom(ctx, types.StringType, []string{"10.0.0.100"})
+	if !got.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+
+	got, _ = addressPairsFromPort(ctx, managed, &api.AttachedPort{})
+	if got.IsNull() || len(got.Elements()) != 0 {
+		t.Errorf("expected pairs removed outside of terraform to be empty, got %s", got)
+	}
+}
+
+func TestPowerStateFromStatus(t *testing.T) {
+	tests := []struct {
+		status   string
+		fallback string
+		expected string
+	}{
+		{"ACTIVE", powerStateStopped, powerStateRunning},
+		{"SHUTOFF", powerStateRunning, powerStateStopped},
+		{statusRescue, powerStateStopped, powerStateRunning},
+		{"REBOOT", powerStateStopped, powerStateStopped},
+		{"RESIZE", powerStateRunning, powerStateRunning},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			if got := powerStateFromStatus(tt.status, tt.fallback); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestDesiredPowerState(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		powerState types.String
+		expected   string
+	}{
+		{"configured stopped", "ACTIVE", types.StringValue(powerStateStopped), powerStateStopped},
+		{"configured running", "SHUTOFF", types.StringValue(powerStateRunning), powerStateRunning},
+		{"unset keeps stopped", "SHUTOFF", types.StringNull(), powerStateStopped},
+		{"unknown keeps running", "ACTIVE", types.StringUnknown(), powerStateRunning},
+		{"unset during transition", "REBOOT", types.StringNull(), powerStateRunning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateData := &models.TFInstanceResourceModel{Status: types.StringValue(tt.status)}
+			planData := &models.TFInstanceResourceModel{PowerState: tt.powerState}
+			if got := desiredPowerState(stateData, planData); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRebootForTriggers(t *testing.T) {
+	triggers := func(v string) types.Map {
+		if v == "" {
+			return types.MapNull(types.StringType)
+		}
+		return types.MapValueMust(types.StringType, map[string]attr.Value{"config": types.StringValue(v)})
+	}
+
+	tests := []struct {
+		name       string
+		state      string
+		plan       string
+		status     string
+		powerState string
+		rebootType string
+		expected   string
+		stopped    bool
+	}{
+		{"unchanged", "v1", "v1", "ACTIVE", powerStateRunning, rebootTypeSoft, "", false},
+		{"triggers added", "", "v1", "ACTIVE", powerStateRunning, rebootTypeSoft, "", false},
+		{"triggers removed", "v1", "", "ACTIVE", powerStateRunning, rebootTypeSoft, "", false},
+		{"soft reboot", "v1", "v2", "ACTIVE", powerStateRunning, rebootTypeSoft, rebootTypeSoft, false},
+		{"hard reboot", "v1", "v2", "ACTIVE", powerStateRunning, rebootTypeHard, rebootTypeHard, false},
+		{"instance stopped", "v1", "v2", "SHUTOFF", powerStateStopped, rebootTypeSoft, "", true},
+		{"instance started after the reboot step", "v1", "v2", "SHUTOFF", powerStateRunning, rebootTypeSoft, "", true},
+		{"instance being stopped", "v1", "v2", "ACTIVE", powerStateStopped, rebootTypeHard, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stateData := &models.TFInstanceResourceModel{
+				Status:         types.StringValue(tt.status),
+				RebootTriggers: triggers(tt.state),
+			}
+			planData := &models.TFInstanceResourceModel{
+				Status:         types.StringValue(tt.status),
+				PowerState:     types.StringValue(tt.powerState),
+				RebootTriggers: triggers(tt.plan),
+				RebootType:     types.StringValue(tt.rebootType),
+			}
+			rebootType, stopped := rebootForTriggers(stateData, planData)
+			if rebootType != tt.expected || stopped != tt.stopped {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.stopped, rebootType, stopped)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package rs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// missingSSHKeys returns the sorted names that are not among keys
+func missingSSHKeys(names []string, keys []*api.SSHKey) []string {
+	known := make(map[string]bool)
+	for _, k := range keys {
+		known[k.Name] = true
+	}
+	var ret []string
+	for _, n := range names {
+		if !known[n] {
+			ret = append(ret, n)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// sshKeyName returns the key_name of the create request, a single name is sent as is to keep
+// the request unchanged for ssh_key_name
+func sshKeyName(ctx context.Context, data *models.TFInstanceResourceModel) (interface{}, bool, diag.Diagnostics) {
+	if !data.SSHKeyName.IsNull() {
+		return data.SSHKeyName.ValueString(), true, nil
+	}
+	if data.SSHKeyNames.IsNull() {
+		return nil, false, nil
+	}
+	var names []string
+	if d := data.SSHKeyNames.ElementsAs(ctx, &names, false); d.HasError() {
+		return nil, false, d
+	}
+	sort.Strings(names)
+	return names, true, nil
+}
+
+// validateSSHKeyNames checks that every name in ssh_key_names exists in the region so a typo
+// fails the plan instead of the create. Keys only matter at creation, so existing instances
+// are not checked again unless ssh_key_names changes
+func (i *InstanceResource) validateSSHKeyNames(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	var names types.Set
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("ssh_key_names"), &names)...)
+	var region types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("region"), &region)...)
+	if resp.Diagnostics.HasError() || names.IsNull() || names.IsUnknown() || region.IsUnknown() {
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var stateNames types.Set
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("ssh_key_names"), &stateNames)...)
+		if resp.Diagnostics.HasError() || names.Equal(stateNames) {
+			return
+		}
+	}
+
+	var known []string
+	for _, v := range names.Elements() {
+		if s, ok := v.(types.String); ok && !s.IsUnknown() {
+			known = append(known, s.ValueString())
+		}
+	}
+	if len(known) == 0 {
+		return
+	}
+
+	keys, err := i.client.SSHClient.GetSSHKeys(ctx, region.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching ssh keys", err.Error())
+		return
+	}
+	if missing := missingSSHKeys(known, keys); len(missing) > 0 {
+		resp.Diagnostics.AddAttributeError(path.Root("ssh_key_names"), "ssh key not found", fmt.Sprintf("ssh keys %s do not exist in region %s", strings.Join(missing, ", "), region.ValueString()))
+	}
+}
@@ -0,0 +1,30 @@
+package rs
+
+import (
+	"reflect"
+	"testing"
+
+	"terraform-provider-hashicups-pf/internal/api"
+)
+
+func TestMissingSSHKeys(t *testing.T) {
+	keys := []*api.SSHKey{{Name: "ops"}, {Name: "ci"}}
+
+	tests := []struct {
+		name     string
+		names    []string
+		expected []string
+	}{
+		{"all present", []string{"ci", "ops"}, nil},
+		{"one missing", []string{"ops", "deploy"}, []string{"deploy"}},
+		{"sorted", []string{"zeta", "ci", "alpha"}, []string{"alpha", "zeta"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingSSHKeys(tt.names, keys)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package rs
+
+import (
+	"context"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func userDataSchema() schema.SingleNestedAttribute {
+	stringList := func() schema.ListAttribute {
+		return schema.ListAttribute{
+			Optional:    true,
+			ElementType: types.StringType,
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"users": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Required: true,
+						},
+						"groups": stringList(),
+						"shell": schema.StringAttribute{
+							Optional: true,
+						},
+						"sudo": schema.StringAttribute{
+							Optional: true,
+						},
+						"ssh_authorized_keys": stringList(),
+					},
+				},
+			},
+			"ssh_authorized_keys": stringList(),
+			"packages":            stringList(),
+			"write_files": schema.ListNestedAttribute{
+				Optional: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"path": schema.StringAttribute{
+							Required: true,
+						},
+						"content": schema.StringAttribute{
+							Required: true,
+						},
+						"permissions": schema.StringAttribute{
+							Optional: true,
+						},
+						"owner": schema.StringAttribute{
+							Optional: true,
+						},
+					},
+				},
+			},
+			"runcmd":  stringList(),
+			"bootcmd": stringList(),
+		},
+	}
+}
+
+// renderUserData renders user_data as cloud-config, combined with the init script if both
+// are set. The result is null when user_data is not set
+func renderUserData(ctx context.Context, userData types.Object, initScript types.String) (types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	data, d := models.UserDataFromObject(ctx, userData)
+	diags.Append(d...)
+	if diags.HasError() || data == nil {
+		return types.StringNull(), diags
+	}
+
+	cfg, d := data.ToCloudConfig(ctx)
+	diags.Append(d...)
+	if diags.HasError() {
+		return types.StringNull(), diags
+	}
+
+	cloudConfig, err := cfg.Render()
+	if err != nil {
+		diags.AddAttributeError(path.Root("user_data"), "invalid user data", err.Error())
+		return types.StringNull(), diags
+	}
+
+	rendered := utl.RenderUserData(cloudConfig, initScript.ValueString())
+	if err := utl.CheckUserDataSize(rendered); err != nil {
+		diags.AddAttributeError(path.Root("user_data"), "invalid user data", err.Error())
+		return types.StringNull(), diags
+	}
+	return types.StringValue(rendered), diags
+}
+
+// renderedUserDataModifier renders user_data at plan time, so the exact document sent to
+// the instance shows up in the plan
+type renderedUserDataModifier struct{}
+
+func (m renderedUserDataModifier) Description(context.Context) string {
+	return ""
+}
+
+func (m renderedUserDataModifier) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+func (m renderedUserDataModifier) PlanModifyString(ctx context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var userData types.Object
+	var initScript types.String
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("user_data"), &userData)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("init_script"), &initScript)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	userDataValue, err := userData.ToTerraformValue(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("error reading user data", err.Error())
+		return
+	}
+	if !userDataValue.IsFullyKnown() || initScript.IsUnknown() {
+		resp.PlanValue = types.StringUnknown()
+		return
+	}
+
+	rendered, d := renderUserData(ctx, userData, initScript)
+	resp.Diagnostics.Append(d...)
+	resp.PlanValue = rendered
+}
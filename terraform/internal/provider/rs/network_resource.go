@@ -33,6 +33,7 @@ func (n *NetworkResource) Configure(ctx context.Context, req resource.ConfigureR
 
 func (n *NetworkResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"region": schema.StringAttribute{
 				Required: true,
@@ -338,6 +339,37 @@ func (n *NetworkResource) handleSimpleUpdates(ctx context.Context, stateData, pl
 	return
 }
 
+func (n *NetworkResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	n.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	return map[int64]resource.StateUpgrader{
+		0: misc.NewJSONStateUpgrader(schemaResp.Schema, upgradeNetworkStateV0),
+	}
+}
+
+// upgradeNetworkStateV0 converts dhcp_range and dns_servers saved in the api format
+// ("start,end" and newline separated servers) to their current shape
+func upgradeNetworkStateV0(state map[string]interface{}) {
+	if dhcpRange, ok := state["dhcp_range"].(string); ok {
+		start, end, _ := strings.Cut(dhcpRange, ",")
+		state["dhcp_range"] = map[string]interface{}{
+			"start": strings.TrimSpace(start),
+			"end":   strings.TrimSpace(end),
+		}
+	}
+
+	if dnsServers, ok := state["dns_servers"].(string); ok {
+		servers := make([]interface{}, 0)
+		for _, server := range strings.Split(dnsServers, "\n") {
+			if server = strings.TrimSpace(server); server != "" {
+				servers = append(servers, server)
+			}
+		}
+		state["dns_servers"] = servers
+	}
+}
+
 func NewNetworkResource() resource.Resource {
 	return &NetworkResource{}
 }
@@ -0,0 +1,340 @@
+package rs
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+)
+
+// PrivateImageResource imports a private image either from an http url or by uploading
+// a local file
+type PrivateImageResource struct {
+	client *api.Client
+}
+
+func (p *PrivateImageResource) SetAPIClient(c *api.Client) {
+	p.client = c
+}
+
+func (p *PrivateImageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_private_image"
+}
+
+func (p *PrivateImageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	misc.ConfigureResource(ctx, &req, resp, p)
+}
+
+// requiresReplaceIfSourceSet lets an imported image adopt its source without being
+// replaced, the source is unknown to the api
+func requiresReplaceIfSourceSet() planmodifier.String {
+	return stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+		resp.RequiresReplace = !req.StateValue.IsNull()
+	}, "changing the image source requires replacement", "changing the image source requires replacement")
+}
+
+func (p *PrivateImageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Delete: true,
+			}),
+		},
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_url": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("source_url"), path.MatchRoot("source_file")),
+				},
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfSourceSet(),
+				},
+			},
+			"source_file": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					requiresReplaceIfSourceSet(),
+				},
+			},
+			"disk_format": schema.StringAttribute{
+				Required: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("qcow2", "raw", "iso"),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"checksum": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+			"progress": schema.Int64Attribute{
+				Computed: true,
+			},
+			"size": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+			"min_disk": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (p *PrivateImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TFPrivateImageModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	createTimeout, d := data.Timeouts.Create(ctx, 60*time.Minute)
+	resp.Diagnostics.Append(d...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, createTimeout)
+	defer cancel()
+
+	region := data.Region.ValueString()
+	expectedChecksum := ""
+	if !data.Checksum.IsNull() && !data.Checksum.IsUnknown() {
+		expectedChecksum = data.Checksum.ValueString()
+	}
+
+	var img *api.PrivateImage
+	var err error
+	if !data.SourceURL.IsNull() {
+		img, err = p.client.Img.ImportImageFromURL(ctx, region, &api.ImportImageRequest{
+			Name:       data.Name.ValueString(),
+			URL:        data.SourceURL.ValueString(),
+			DiskFormat: data.DiskFormat.ValueString(),
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("error importing private image", err.Error())
+			return
+		}
+	} else {
+		f, err := os.Open(data.SourceFile.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("error opening image file", err.Error())
+			return
+		}
+		defer f.Close()
+
+		h := md5.New()
+		img, err = p.client.Img.UploadImage(ctx, region, data.Name.ValueString(), data.DiskFormat.ValueString(), filepath.Base(f.Name()), io.TeeReader(f, h))
+		if err != nil {
+			resp.Diagnostics.AddError("error uploading private image", err.Error())
+			return
+		}
+
+		uploaded := hex.EncodeToString(h.Sum(nil))
+		if expectedChecksum != "" && !strings.EqualFold(expectedChecksum, uploaded) {
+			p.deleteAfterFailure(ctx, region, img.ID, resp)
+			resp.Diagnostics.AddError("checksum mismatch", fmt.Sprintf("uploaded file has checksum %s, expected %s", uploaded, expectedChecksum))
+			return
+		}
+		expectedChecksum = uploaded
+	}
+	data.ID = types.StringValue(img.ID)
+
+	var failure error
+	err = p.client.WaitForCondition(ctx, createTimeout, func() (bool, error) {
+		x, err := p.client.Img.GetPrivateImageByID(ctx, region, data.ID.ValueString())
+		if err != nil {
+			return false, err
+		}
+		img = x
+		switch strings.ToLower(x.Status) {
+		case "active":
+			return true, nil
+		case "killed", "error", "deleted":
+			failure = fmt.Errorf("image status transitioned into %s", x.Status)
+			return true, nil
+		}
+		return false, nil
+	})
+	if err == nil {
+		err = failure
+	}
+	if err != nil {
+		p.deleteAfterFailure(ctx, region, data.ID.ValueString(), resp)
+		resp.Diagnostics.AddError("error waiting for private image", err.Error())
+		return
+	}
+
+	if expectedChecksum != "" && img.Checksum != "" && !strings.EqualFold(expectedChecksum, img.Checksum) {
+		p.deleteAfterFailure(ctx, region, data.ID.ValueString(), resp)
+		resp.Diagnostics.AddError("checksum mismatch", fmt.Sprintf("image has checksum %s, expected %s", img.Checksum, expectedChecksum))
+		return
+	}
+
+	data.Checksum = types.StringValue(img.Checksum)
+	if img.Checksum == "" {
+		data.Checksum = types.StringValue(expectedChecksum)
+	}
+	data.Status = types.StringValue(img.Status)
+	data.Progress = types.Int64Value(100)
+	data.Size = types.Int64Value(img.Size)
+	data.MinDisk = types.Int64Value(int64(img.MinDisk))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// deleteAfterFailure removes an image which did not make it, so a failed create does not
+// leave an untracked image behind
+func (p *PrivateImageResource) deleteAfterFailure(ctx context.Context, region, id string, resp *resource.CreateResponse) {
+	err := p.client.Img.DeletePrivateImage(ctx, region, id)
+	if err != nil {
+		resp.Diagnostics.AddWarning("error cleaning up private image", err.Error())
+	}
+}
+
+func (p *PrivateImageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.TFPrivateImageModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	img, err := p.client.Img.GetPrivateImageByID(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		if misc.RemoveResourceIfNotFound(ctx, resp, err) {
+			return
+		}
+		resp.Diagnostics.AddError("error fetching private image", err.Error())
+		return
+	}
+	utl.AssignStringIfChanged(&data.Name, img.Name)
+	utl.AssignStringIfChanged(&data.Status, img.Status)
+	utl.AssignStringIfChanged(&data.DiskFormat, img.DiskFormat)
+	if data.Checksum.ValueString() == "" {
+		data.Checksum = types.StringValue(img.Checksum)
+	}
+	data.Progress = types.Int64Value(int64(img.Progress))
+	if strings.ToLower(img.Status) == "active" {
+		data.Progress = types.Int64Value(100)
+	}
+	data.Size = types.Int64Value(img.Size)
+	data.MinDisk = types.Int64Value(int64(img.MinDisk))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (p *PrivateImageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var planData models.TFPrivateImageModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var stateData models.TFPrivateImageModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// only the source of an imported image or timeouts can change in place
+	planData.Status = stateData.Status
+	planData.Progress = stateData.Progress
+	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
+}
+
+func (p *PrivateImageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TFPrivateImageModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := p.client.Img.DeletePrivateImage(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+			return
+		}
+		resp.Diagnostics.AddError("error deleting private image", err.Error())
+		return
+	}
+
+	deleteTimeout, d := data.Timeouts.Delete(ctx, 5*time.Minute)
+	resp.Diagnostics.Append(d...)
+
+	err = p.client.WaitForCondition(ctx, deleteTimeout, func() (bool, error) {
+		_, err := p.client.Img.GetPrivateImageByID(ctx, data.Region.ValueString(), data.ID.ValueString())
+		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+			return true, nil
+		}
+		return false, err
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("error deleting private image", err.Error())
+	}
+}
+
+func (p *PrivateImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	region, id, ok := strings.Cut(req.ID, "/")
+	if !ok || region == "" || id == "" {
+		resp.Diagnostics.AddError("invalid import id", "import id must be in the form <region>/<image_id>")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), region)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+func NewPrivateImageResource() resource.Resource {
+	return &PrivateImageResource{}
+}
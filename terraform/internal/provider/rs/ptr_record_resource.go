@@ -0,0 +1,146 @@
+package rs
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+)
+
+// PTRRecordResource manages the reverse dns record of a public or floating ip
+type PTRRecordResource struct {
+	client *api.Client
+}
+
+func (p *PTRRecordResource) SetAPIClient(c *api.Client) {
+	p.client = c
+}
+
+func (p *PTRRecordResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ptr_record"
+}
+
+func (p *PTRRecordResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	misc.ConfigureResource(ctx, &req, resp, p)
+}
+
+func (p *PTRRecordResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ip": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"domain": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+func (p *PTRRecordResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TFPTRRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := p.client.PTR.SetPTR(ctx, data.Region.ValueString(), data.IP.ValueString(), data.Domain.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error setting ptr record", err.Error())
+		return
+	}
+	data.ID = data.IP
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (p *PTRRecordResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.TFPTRRecordModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ptr, err := p.client.PTR.GetPTR(ctx, data.Region.ValueString(), data.IP.ValueString())
+	if err != nil {
+		if misc.RemoveResourceIfNotFound(ctx, resp, err) {
+			return
+		}
+		resp.Diagnostics.AddError("error fetching ptr record", err.Error())
+		return
+	}
+	utl.AssignStringIfChanged(&data.Domain, ptr.Domain)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (p *PTRRecordResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var planData models.TFPTRRecordModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := p.client.PTR.SetPTR(ctx, planData.Region.ValueString(), planData.IP.ValueString(), planData.Domain.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error setting ptr record", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
+}
+
+func (p *PTRRecordResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TFPTRRecordModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := p.client.PTR.DeletePTR(ctx, data.Region.ValueString(), data.IP.ValueString())
+	if err != nil {
+		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+			return
+		}
+		resp.Diagnostics.AddError("error deleting ptr record", err.Error())
+		return
+	}
+}
+
+func (p *PTRRecordResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	region, ip, ok := strings.Cut(req.ID, "/")
+	if !ok || region == "" || ip == "" {
+		resp.Diagnostics.AddError("invalid import id", "import id must be in the form <region>/<ip>")
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("region"), region)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), ip)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ip"), ip)...)
+}
+
+func NewPTRRecordResource() resource.Resource {
+	return &PTRRecordResource{}
+}
@@ -3,6 +3,7 @@ package rs
 import (
 	"context"
 	"net"
+	"strconv"
 	"terraform-provider-hashicups-pf/internal/api"
 	"terraform-provider-hashicups-pf/internal/provider/misc"
 	"terraform-provider-hashicups-pf/internal/provider/models"
@@ -37,6 +38,7 @@ func (s *SecurityGroupResource) Configure(ctx context.Context, req resource.Conf
 
 func (s *SecurityGroupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Version: 1,
 		Attributes: map[string]schema.Attribute{
 			"region": schema.StringAttribute{
 				Required: true,
@@ -373,6 +375,34 @@ func (s *SecurityGroupResource) populateCreatedRule(ctx context.Context, state *
 	return d
 }
 
+func (s *SecurityGroupResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	s.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+	return map[int64]resource.StateUpgrader{
+		0: misc.NewJSONStateUpgrader(schemaResp.Schema, upgradeSecurityGroupStateV0),
+	}
+}
+
+// upgradeSecurityGroupStateV0 converts rule ports saved as numbers to strings
+func upgradeSecurityGroupStateV0(state map[string]interface{}) {
+	rules, ok := state["rules"].([]interface{})
+	if !ok {
+		return
+	}
+	for _, item := range rules {
+		rule, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"port_from", "port_to"} {
+			if port, ok := rule[key].(float64); ok {
+				rule[key] = strconv.Itoa(int(port))
+			}
+		}
+	}
+}
+
 func NewSecurityGroupResource() resource.Resource {
 	return &SecurityGroupResource{}
 }
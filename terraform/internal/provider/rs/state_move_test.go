@@ -0,0 +1,227 @@
+package rs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+type movableResource interface {
+	resource.Resource
+	resource.ResourceWithMoveState
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// fakeClient answers every request with the body registered for its path and 404 otherwise,
+// calls counts the requests per path
+func fakeClient(responses map[string]string) (*api.Client, map[string]int) {
+	calls := make(map[string]int)
+	r := api.NewRequester(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls[req.URL.Path]++
+		code, body := http.StatusOK, responses[req.URL.Path]
+		if body == "" {
+			code, body = http.StatusNotFound, `{"message":"not found"}`
+		}
+		return &http.Response{
+			StatusCode: code,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})}, "apikey test")
+
+	return &api.Client{
+		VolumeV2: api.NewVolumeV2Client(r),
+		BackupV2: api.NewBackupV2Client(r),
+	}, calls
+}
+
+// moveFixture runs the state mover of res on testdata/name saved as sourceType and returns
+// the target state
+func moveFixture(t *testing.T, res movableResource, sourceType, name string) resource.MoveStateResponse {
+	t.Helper()
+	ctx := context.Background()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	movers := res.MoveState(ctx)
+	if len(movers) != 1 {
+		t.Fatalf("expected one state mover, got %d", len(movers))
+	}
+	mover := movers[0]
+
+	rawState := &tfprotov6.RawState{JSON: raw}
+	value, err := rawState.Unmarshal(mover.SourceSchema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := tfsdk.State{Raw: value, Schema: *mover.SourceSchema}
+
+	var schemaResp resource.SchemaResponse
+	res.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	resp := resource.MoveStateResponse{
+		TargetState: tfsdk.State{
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			Schema: schemaResp.Schema,
+		},
+	}
+	mover.StateMover(ctx, resource.MoveStateRequest{
+		SourceTypeName: sourceType,
+		SourceRawState: rawState,
+		SourceState:    &source,
+	}, &resp)
+	return resp
+}
+
+func TestMoveVolumeState(t *testing.T) {
+	ctx := context.Background()
+	const (
+		listPath    = "/ecc/v2/volume/ir-thr-ba1/list"
+		inquiryPath = "/ecc/v2/volume/ir-thr-ba1/inquiry/5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4"
+	)
+
+	tests := []struct {
+		name   string
+		labels string
+		tags   []string
+	}{
+		{"labels", `["web","prod"]`, []string{"prod", "web"}},
+		{"no labels", `null`, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, calls := fakeClient(map[string]string{
+				listPath:    `{"data":[{"id":"other","type":"ssd"},{"id":"5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4","name":"web-data","size":20,"type":"ssd","labels":` + tt.labels + `}]}`,
+				inquiryPath: `{"name":"web-data","size":20,"status":"in-use","instance_name":"web-1"}`,
+			})
+			resp := moveFixture(t, &VolumeV2Resource{c: client}, "arvan_volume", "volume.json")
+			if resp.Diagnostics.HasError() {
+				t.Fatal(resp.Diagnostics)
+			}
+
+			var data models.TFVolumeV2Model
+			if d := resp.TargetState.Get(ctx, &data); d.HasError() {
+				t.Fatal(d)
+			}
+			if data.Region.ValueString() != "ir-thr-ba1" || data.ID.ValueString() != "5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4" || data.Name.ValueString() != "web-data" || data.Size.ValueInt64() != 20 {
+				t.Errorf("unexpected attributes region=%s id=%s name=%s size=%s", data.Region, data.ID, data.Name, data.Size)
+			}
+			// the api knows the actual type, the old resource may have it unset
+			if data.Type.ValueString() != "ssd" {
+				t.Errorf("expected type from the api, got %s", data.Type)
+			}
+			if data.Status.ValueString() != "in-use" || data.InstanceName.ValueString() != "web-1" {
+				t.Errorf("unexpected status=%s instance_name=%s", data.Status, data.InstanceName)
+			}
+			if !data.SnapshotID.IsNull() {
+				t.Errorf("expected snapshot_id to be null, got %s", data.SnapshotID)
+			}
+
+			tags, d := data.GetTags(ctx)
+			if d.HasError() {
+				t.Fatal(d)
+			}
+			sort.Strings(tags)
+			if data.Tags.IsNull() || !reflect.DeepEqual(append([]string{}, tags...), tt.tags) {
+				t.Errorf("expected tags %v, got %s", tt.tags, data.Tags)
+			}
+
+			if calls[listPath] != 1 || calls[inquiryPath] != 1 {
+				t.Errorf("expected one call to list and inquiry, got %v", calls)
+			}
+		})
+	}
+}
+
+func TestMoveVolumeStateErrors(t *testing.T) {
+	t.Run("volume gone", func(t *testing.T) {
+		client, _ := fakeClient(map[string]string{
+			"/ecc/v2/volume/ir-thr-ba1/list": `{"data":[]}`,
+		})
+		resp := moveFixture(t, &VolumeV2Resource{c: client}, "arvan_volume", "volume.json")
+		if !resp.Diagnostics.HasError() {
+			t.Error("expected an error when the volume no longer exists")
+		}
+	})
+
+	t.Run("unrelated source", func(t *testing.T) {
+		client, calls := fakeClient(nil)
+		resp := moveFixture(t, &VolumeV2Resource{c: client}, "arvan_network", "volume.json")
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		if !resp.TargetState.Raw.IsNull() {
+			t.Errorf("expected the target state to stay null, got %s", resp.TargetState.Raw)
+		}
+		if len(calls) != 0 {
+			t.Errorf("expected no api calls, got %v", calls)
+		}
+	})
+}
+
+func TestMoveVolumeSnapshotState(t *testing.T) {
+	ctx := context.Background()
+	const detailsPath = "/ecc/v2/snapshot/ir-thr-ba1/volume/5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4/details"
+
+	client, calls := fakeClient(map[string]string{
+		detailsPath: `{"id":"5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4","snapshots":[{"id":"other","labels":["x"]},{"id":"a7c2e9f1-3b4d-4e5f-8a9b-0c1d2e3f4a5b","name":"web-data-snap","labels":["nightly"]}]}`,
+	})
+	resp := moveFixture(t, &VolumeSnapshotV2Resource{client: client}, "arvan_volume_snapshot", "volume_snapshot.json")
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	var data models.TFVolumeSnapshotV2
+	if d := resp.TargetState.Get(ctx, &data); d.HasError() {
+		t.Fatal(d)
+	}
+	if data.Region.ValueString() != "ir-thr-ba1" || data.ID.ValueString() != "a7c2e9f1-3b4d-4e5f-8a9b-0c1d2e3f4a5b" || data.Name.ValueString() != "web-data-snap" || data.VolumeID.ValueString() != "5f0d6b2e-8a51-4b9e-a7e4-90a3c5d1e2f4" {
+		t.Errorf("unexpected attributes region=%s id=%s name=%s volume_id=%s", data.Region, data.ID, data.Name, data.VolumeID)
+	}
+
+	var labels []string
+	if d := data.Labels.ElementsAs(ctx, &labels, false); d.HasError() {
+		t.Fatal(d)
+	}
+	if len(labels) != 1 || labels[0] != "nightly" {
+		t.Errorf("expected labels [nightly], got %v", labels)
+	}
+	if calls[detailsPath] != 1 {
+		t.Errorf("expected one call to snapshot details, got %v", calls)
+	}
+
+	t.Run("unrelated source", func(t *testing.T) {
+		client, calls := fakeClient(nil)
+		resp := moveFixture(t, &VolumeSnapshotV2Resource{client: client}, "arvan_volume", "volume_snapshot.json")
+		if resp.Diagnostics.HasError() {
+			t.Fatal(resp.Diagnostics)
+		}
+		if !resp.TargetState.Raw.IsNull() {
+			t.Errorf("expected the target state to stay null, got %s", resp.TargetState.Raw)
+		}
+		if len(calls) != 0 {
+			t.Errorf("expected no api calls, got %v", calls)
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package rs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+)
+
+type upgradableResource interface {
+	resource.Resource
+	resource.ResourceWithUpgradeState
+}
+
+// upgradeFixture runs the version 0 upgrader of res on testdata/name and returns the
+// upgraded state
+func upgradeFixture(t *testing.T, res upgradableResource, name string) tfsdk.State {
+	t.Helper()
+	ctx := context.Background()
+
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schemaResp resource.SchemaResponse
+	res.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	if schemaResp.Schema.Version != 1 {
+		t.Fatalf("expected schema version 1, got %d", schemaResp.Schema.Version)
+	}
+
+	upgrader, ok := res.UpgradeState(ctx)[0]
+	if !ok {
+		t.Fatal("no upgrader for version 0")
+	}
+
+	var resp resource.UpgradeStateResponse
+	upgrader.StateUpgrader(ctx, resource.UpgradeStateRequest{RawState: &tfprotov6.RawState{JSON: raw}}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+
+	value, err := resp.DynamicValue.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tfsdk.State{Raw: value, Schema: schemaResp.Schema}
+}
+
+func TestUpgradeInstanceState(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		fixture    string
+		powerState string
+		ports      []string
+	}{
+		// networks come back sorted by network_id
+		{"abrak_v0.json", powerStateRunning, []string{"d52121fd-6b63-4fb5-ae99-2e76052bfeeb", "62981aca-abd7-413e-aa08-203db187ae7c"}},
+		{"abrak_v0_legacy.json", powerStateStopped, []string{"", ""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			state := upgradeFixture(t, &InstanceResource{}, tt.fixture)
+
+			var data models.TFInstanceResourceModel
+			if d := state.Get(ctx, &data); d.HasError() {
+				t.Fatal(d)
+			}
+			if data.ID.ValueString() != "2c1003a8-60d9-4477-9321-e6eed2fcc58f" {
+				t.Errorf("unexpected id %q", data.ID.ValueString())
+			}
+			if data.PowerState.ValueString() != tt.powerState {
+				t.Errorf("expected power_state %q, got %q", tt.powerState, data.PowerState.ValueString())
+			}
+			if data.RebootType.ValueString() != rebootTypeSoft {
+				t.Errorf("expected reboot_type %q, got %q", rebootTypeSoft, data.RebootType.ValueString())
+			}
+			if !data.RebuildOnImageChange.Equal(types.BoolValue(false)) || !data.DeletionProtection.Equal(types.BoolValue(false)) {
+				t.Errorf("unexpected defaults rebuild_on_image_change=%s deletion_protection=%s", data.RebuildOnImageChange, data.DeletionProtection)
+			}
+			if data.DeleteMode.ValueString() != deleteModeForce {
+				t.Errorf("expected delete_mode %q, got %q", deleteModeForce, data.DeleteMode.ValueString())
+			}
+			if !data.StorePassword.Equal(types.BoolValue(true)) {
+				t.Errorf("expected store_password to default to true, got %s", data.StorePassword)
+			}
+			if data.MigrationMode.ValueString() != migrationModeCold {
+				t.Errorf("expected migration_mode %q, got %q", migrationModeCold, data.MigrationMode.ValueString())
+			}
+			if !data.Tags.IsNull() {
+				t.Errorf("expected tags to be null, got %s", data.Tags)
+			}
+
+			networks, d := data.GetNetworkAttachments(ctx)
+			if d.HasError() {
+				t.Fatal(d)
+			}
+			if len(networks) != len(tt.ports) {
+				t.Fatalf("expected %d networks, got %d", len(tt.ports), len(networks))
+			}
+			for idx, network := range networks {
+				if network.NetworkID.ValueString() == "" {
+					t.Errorf("network %d has no network_id", idx)
+				}
+				if network.PortID.ValueString() != tt.ports[idx] {
+					t.Errorf("network %d: expected port %q, got %q", idx, tt.ports[idx], network.PortID.ValueString())
+				}
+				if network.IsPublic.ValueBool() || !network.PortSecurityEnabled.ValueBool() {
+					t.Errorf("network %d: unexpected flags is_public=%s port_security_enabled=%s", idx, network.IsPublic, network.PortSecurityEnabled)
+				}
+				if !network.PTR.IsNull() || !network.AllowedAddressPairs.IsNull() {
+					t.Errorf("network %d: expected ptr and allowed_address_pairs to be null", idx)
+				}
+			}
+
+			timeouts := data.Timeouts.Attributes()
+			if len(timeouts) != 4 {
+				t.Errorf("expected timeouts to be kept, got %v", timeouts)
+			}
+		})
+	}
+}
+
+func TestUpgradeNetworkState(t *testing.T) {
+	ctx := context.Background()
+
+	for _, fixture := range []string{"network_v0.json", "network_v0_legacy.json"} {
+		t.Run(fixture, func(t *testing.T) {
+			state := upgradeFixture(t, &NetworkResource{}, fixture)
+
+			var data models.TFSubnetModel
+			if d := state.Get(ctx, &data); d.HasError() {
+				t.Fatal(d)
+			}
+			if data.CIDR.ValueString() != "192.168.88.0/24" {
+				t.Errorf("unexpected cidr %q", data.CIDR.ValueString())
+			}
+
+			dhcpRange, d := data.GetDHCPRange(ctx)
+			if d.HasError() {
+				t.Fatal(d)
+			}
+			if dhcpRange.Start.ValueString() != "192.168.88.10" || dhcpRange.End.ValueString() != "192.168.88.200" {
+				t.Errorf("unexpected dhcp_range %s - %s", dhcpRange.Start, dhcpRange.End)
+			}
+
+			var servers []string
+			if d := data.DNSServer.ElementsAs(ctx, &servers, false); d.HasError() {
+				t.Fatal(d)
+			}
+			if len(servers) != 2 || servers[0] != "8.8.8.8" || servers[1] != "1.1.1.1" {
+				t.Errorf("unexpected dns_servers %v", servers)
+			}
+		})
+	}
+}
+
+func TestUpgradeSecurityGroupState(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		fixture string
+		rules   int
+		ports   map[string]string
+	}{
+		{"security_group_v0.json", 8, map[string]string{}},
+		{"security_group_v0_legacy.json", 2, map[string]string{"22": "22", "80": "443"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			state := upgradeFixture(t, &SecurityGroupResource{}, tt.fixture)
+
+			var data models.TFSecurityGroupModel
+			if d := state.Get(ctx, &data); d.HasError() {
+				t.Fatal(d)
+			}
+			if data.ID.ValueString() != "2db5222c-14c5-4e0b-89e3-34bfa46034f4" {
+				t.Errorf("unexpected id %q", data.ID.ValueString())
+			}
+
+			rules, d := data.GetRules(ctx)
+			if d.HasError() {
+				t.Fatal(d)
+			}
+			if len(rules) != tt.rules {
+				t.Fatalf("expected %d rules, got %d", tt.rules, len(rules))
+			}
+			ports := map[string]string{}
+			for _, rule := range rules {
+				if !rule.PortFrom.IsNull() {
+					ports[rule.PortFrom.ValueString()] = rule.PortTo.ValueString()
+				}
+			}
+			if len(ports) != len(tt.ports) {
+				t.Fatalf("expected ports %v, got %v", tt.ports, ports)
+			}
+			for from, to := range tt.ports {
+				if ports[from] != to {
+					t.Errorf("expected port range %s-%s, got %s-%s", from, to, from, ports[from])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package rs
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"terraform-provider-hashicups-pf/internal/api"
+	"terraform-provider-hashicups-pf/internal/provider/misc"
+	"terraform-provider-hashicups-pf/internal/provider/models"
+	"terraform-provider-hashicups-pf/internal/utl"
+)
+
+// VirtualIPResource reserves an address in a private network which is not bound to
+// any instance, instances can then announce it by listing it in the allowed address
+// pairs of their network attachment (e.g. a keepalived vip)
+type VirtualIPResource struct {
+	client *api.Client
+}
+
+func (v *VirtualIPResource) SetAPIClient(c *api.Client) {
+	v.client = c
+}
+
+func (v *VirtualIPResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_virtual_ip"
+}
+
+func (v *VirtualIPResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	misc.ConfigureResource(ctx, &req, resp, v)
+}
+
+func (v *VirtualIPResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"region": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"network_id": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"ip": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"subnet_id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"mac_address": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (v *VirtualIPResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TFVirtualIPModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	subnet, err := v.client.Subnet.GetNetworkSubnet(ctx, data.Region.ValueString(), data.NetworkID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("error fetching subnet", err.Error())
+		return
+	}
+
+	port, err := v.client.Subnet.CreatePort(ctx, data.Region.ValueString(), data.NetworkID.ValueString(), &api.CreatePortRequest{
+		Name:     data.Name.ValueString(),
+		SubnetID: subnet.ID,
+		IP:       data.IP.ValueString(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("error creating virtual ip", err.Error())
+		return
+	}
+
+	data.ID = types.StringValue(port.ID)
+	data.SubnetID = types.StringValue(subnet.ID)
+	data.IP = types.StringValue(port.IPAddress)
+	data.MacAddress = types.StringValue(port.MacAddr)
+	data.Status = types.StringValue(port.Status)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (v *VirtualIPResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var data models.TFVirtualIPModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	port, err := v.client.Subnet.GetPort(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		if misc.RemoveResourceIfNotFound(ctx, resp, err) {
+			return
+		}
+		resp.Diagnostics.AddError("error fetching virtual ip", err.Error())
+		return
+	}
+	utl.AssignStringIfChanged(&data.IP, port.IPAddress)
+	utl.AssignStringIfChanged(&data.MacAddress, port.MacAddr)
+	utl.AssignStringIfChanged(&data.Status, port.Status)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+func (v *VirtualIPResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TFVirtualIPModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := v.client.Subnet.DeletePort(ctx, data.Region.ValueString(), data.ID.ValueString())
+	if err != nil {
+		if respErr, ok := err.(*api.ResponseError); ok && respErr.Code == 404 {
+			return
+		}
+		resp.Diagnostics.AddError("error deleting virtual ip", err.Error())
+		return
+	}
+}
+
+func (v *VirtualIPResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var planData models.TFVirtualIPModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var stateData models.TFVirtualIPModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &stateData)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	planData.Status = stateData.Status
+	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
+}
+
+func NewVirtualIPResource() resource.Resource {
+	return &VirtualIPResource{}
+}
@@ -37,6 +37,7 @@ func (v *VolumeResource) Configure(ctx context.Context, req resource.ConfigureRe
 
 func (v *VolumeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		DeprecationMessage: "use arvan_volume_v2 instead, existing resources can be migrated with a moved block",
 		Attributes: map[string]schema.Attribute{
 			"region": schema.StringAttribute{
 				Required: true,
@@ -38,6 +38,7 @@ func (v *VolumeSnapshotResource) Configure(ctx context.Context, req resource.Con
 
 func (v *VolumeSnapshotResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		DeprecationMessage: "use arvan_volume_snapshot_v2 instead, existing resources can be migrated with a moved block",
 		Attributes: map[string]schema.Attribute{
 			"region": schema.StringAttribute{
 				Required: true,
@@ -172,6 +172,53 @@ func (s *VolumeSnapshotV2Resource) Update(ctx context.Context, req resource.Upda
 	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
 }
 
+// MoveState lets a `moved` block turn an arvan_volume_snapshot into an
+// arvan_volume_snapshot_v2, labels are carried over from the api
+func (s *VolumeSnapshotV2Resource) MoveState(ctx context.Context) []resource.StateMover {
+	var sourceSchema resource.SchemaResponse
+	(&VolumeSnapshotResource{}).Schema(ctx, resource.SchemaRequest{}, &sourceSchema)
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &sourceSchema.Schema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "arvan_volume_snapshot" || req.SourceState == nil {
+					return
+				}
+
+				var source models.VolumeSnapshot
+				resp.Diagnostics.Append(req.SourceState.Get(ctx, &source)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := models.TFVolumeSnapshotV2{
+					Region:   source.Region,
+					ID:       source.ID,
+					Name:     source.Name,
+					VolumeID: source.VolumeID,
+				}
+
+				apiResp, err := s.client.BackupV2.GetVolumeSnapshot(ctx, source.Region.ValueString(), source.VolumeID.ValueString(), source.ID.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddError("error fetching volume snapshot", err.Error())
+					return
+				}
+				labels := apiResp.Labels
+				if labels == nil {
+					labels = []string{}
+				}
+				resp.Diagnostics.Append(data.SetLabels(ctx, labels)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
 func NewVolumeSnapshotV2Resource() resource.Resource {
 	return &VolumeSnapshotV2Resource{}
 }
@@ -323,6 +323,68 @@ func (v *VolumeV2Resource) Update(ctx context.Context, req resource.UpdateReques
 	resp.Diagnostics.Append(resp.State.Set(ctx, &planData)...)
 }
 
+// MoveState lets a `moved` block turn an arvan_volume into an arvan_volume_v2 without
+// recreating the disk, labels are carried over from the api
+func (v *VolumeV2Resource) MoveState(ctx context.Context) []resource.StateMover {
+	var sourceSchema resource.SchemaResponse
+	(&VolumeResource{}).Schema(ctx, resource.SchemaRequest{}, &sourceSchema)
+
+	return []resource.StateMover{
+		{
+			SourceSchema: &sourceSchema.Schema,
+			StateMover: func(ctx context.Context, req resource.MoveStateRequest, resp *resource.MoveStateResponse) {
+				if req.SourceTypeName != "arvan_volume" || req.SourceState == nil {
+					return
+				}
+
+				var source models.TFVolumeModel
+				resp.Diagnostics.Append(req.SourceState.Get(ctx, &source)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				data := models.TFVolumeV2Model{
+					Region:       source.Region,
+					ID:           source.ID,
+					Name:         source.Name,
+					Size:         source.Size,
+					Type:         source.Type,
+					InstanceName: types.StringValue(""),
+					Status:       types.StringNull(),
+					SnapshotID:   types.StringNull(),
+				}
+
+				details, err := v.c.VolumeV2.GetVolumeByID(ctx, source.Region.ValueString(), source.ID.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddError("error fetching volume labels", err.Error())
+					return
+				}
+				if details.VolumeType != "" {
+					data.Type = types.StringValue(details.VolumeType)
+				}
+				labels := details.Labels
+				if labels == nil {
+					labels = []string{}
+				}
+				resp.Diagnostics.Append(data.SetTags(ctx, labels)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				inqData, err := v.c.VolumeV2.Inquire(ctx, source.Region.ValueString(), source.ID.ValueString())
+				if err != nil {
+					resp.Diagnostics.AddError("error fetching volume details", err.Error())
+					return
+				}
+				data.Status = types.StringValue(inqData.Status)
+				data.InstanceName = types.StringValue(inqData.InstanceName)
+
+				resp.Diagnostics.Append(resp.TargetState.Set(ctx, &data)...)
+			},
+		},
+	}
+}
+
 func NewVolumeV2Resource() resource.Resource {
 	return &VolumeV2Resource{}
 }
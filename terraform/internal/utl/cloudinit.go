@@ -0,0 +1,206 @@
+package utl
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// MaxUserDataSize is the limit on base64 encoded user data accepted by the api
+const MaxUserDataSize = 65535
+
+var filePermissionsRegex = regexp.MustCompile(`^0?[0-7]{3}$`)
+
+type CloudConfigUser struct {
+	Name              string
+	Groups            []string
+	Shell             string
+	Sudo              string
+	SSHAuthorizedKeys []string
+}
+
+type CloudConfigFile struct {
+	Path        string
+	Content     string
+	Permissions string
+	Owner       string
+}
+
+type CloudConfig struct {
+	Users             []CloudConfigUser
+	SSHAuthorizedKeys []string
+	Packages          []string
+	WriteFiles        []CloudConfigFile
+	RunCmd            []string
+	BootCmd           []string
+}
+
+func (c *CloudConfig) Validate() error {
+	users := make(map[string]bool)
+	for idx, u := range c.Users {
+		if strings.TrimSpace(u.Name) == "" {
+			return fmt.Errorf("users[%d]: name must not be empty", idx)
+		}
+		if users[u.Name] {
+			return fmt.Errorf("users[%d]: duplicate user %q", idx, u.Name)
+		}
+		users[u.Name] = true
+	}
+
+	for idx, f := range c.WriteFiles {
+		if !path.IsAbs(f.Path) {
+			return fmt.Errorf("write_files[%d]: path %q must be absolute", idx, f.Path)
+		}
+		if f.Permissions != "" && !filePermissionsRegex.MatchString(f.Permissions) {
+			return fmt.Errorf("write_files[%d]: permissions %q must be in octal notation, e.g. 0644", idx, f.Permissions)
+		}
+	}
+
+	for idx, cmd := range c.RunCmd {
+		if strings.TrimSpace(cmd) == "" {
+			return fmt.Errorf("runcmd[%d]: command must not be empty", idx)
+		}
+	}
+	for idx, cmd := range c.BootCmd {
+		if strings.TrimSpace(cmd) == "" {
+			return fmt.Errorf("bootcmd[%d]: command must not be empty", idx)
+		}
+	}
+	return nil
+}
+
+// Render validates the config and renders it as a #cloud-config document. Keys and
+// list items are written one per line so changes are easy to spot in a plan
+func (c *CloudConfig) Render() (string, error) {
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("#cloud-config\n")
+
+	if len(c.Users) > 0 {
+		b.WriteString("users:\n")
+		for _, u := range c.Users {
+			b.WriteString("  - name: " + yamlQuote(u.Name) + "\n")
+			if len(u.Groups) > 0 {
+				b.WriteString("    groups: " + yamlQuote(strings.Join(u.Groups, ", ")) + "\n")
+			}
+			if u.Shell != "" {
+				b.WriteString("    shell: " + yamlQuote(u.Shell) + "\n")
+			}
+			if u.Sudo != "" {
+				b.WriteString("    sudo: " + yamlQuote(u.Sudo) + "\n")
+			}
+			writeYAMLList(&b, "    ", "ssh_authorized_keys", u.SSHAuthorizedKeys)
+		}
+	}
+
+	writeYAMLList(&b, "", "ssh_authorized_keys", c.SSHAuthorizedKeys)
+	writeYAMLList(&b, "", "packages", c.Packages)
+
+	if len(c.WriteFiles) > 0 {
+		b.WriteString("write_files:\n")
+		for _, f := range c.WriteFiles {
+			b.WriteString("  - path: " + yamlQuote(f.Path) + "\n")
+			if f.Permissions != "" {
+				b.WriteString("    permissions: " + yamlQuote(f.Permissions) + "\n")
+			}
+			if f.Owner != "" {
+				b.WriteString("    owner: " + yamlQuote(f.Owner) + "\n")
+			}
+			b.WriteString("    content: " + yamlBlock(f.Content, "      ") + "\n")
+		}
+	}
+
+	writeYAMLList(&b, "", "bootcmd", c.BootCmd)
+	writeYAMLList(&b, "", "runcmd", c.RunCmd)
+
+	return b.String(), nil
+}
+
+// RenderUserData combines a cloud-config document with a shell script into a multipart
+// MIME document, if only one of them is set it is returned as is
+func RenderUserData(cloudConfig, script string) string {
+	if cloudConfig == "" || script == "" {
+		return cloudConfig + script
+	}
+
+	sum := sha256.Sum256([]byte(cloudConfig + script))
+	boundary := "MIMEBOUNDARY-" + hex.EncodeToString(sum[:8])
+
+	var b strings.Builder
+	b.WriteString("Content-Type: multipart/mixed; boundary=\"" + boundary + "\"\n")
+	b.WriteString("MIME-Version: 1.0\n")
+	writeMIMEPart(&b, boundary, "text/cloud-config", "cloud-config.txt", cloudConfig)
+	writeMIMEPart(&b, boundary, "text/x-shellscript", "init-script.sh", script)
+	b.WriteString("\n--" + boundary + "--\n")
+	return b.String()
+}
+
+// CheckUserDataSize returns an error if the user data exceeds the api limit once encoded
+func CheckUserDataSize(userData string) error {
+	size := base64.StdEncoding.EncodedLen(len(userData))
+	if size > MaxUserDataSize {
+		return fmt.Errorf("user data is %d bytes when encoded, the limit is %d bytes", size, MaxUserDataSize)
+	}
+	return nil
+}
+
+func writeMIMEPart(b *strings.Builder, boundary, contentType, fileName, content string) {
+	b.WriteString("\n--" + boundary + "\n")
+	b.WriteString("Content-Type: " + contentType + "; charset=\"utf-8\"\n")
+	b.WriteString("MIME-Version: 1.0\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\n")
+	b.WriteString("Content-Disposition: attachment; filename=\"" + fileName + "\"\n\n")
+	b.WriteString(strings.TrimSuffix(content, "\n") + "\n")
+}
+
+func writeYAMLList(b *strings.Builder, indent, key string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	b.WriteString(indent + key + ":\n")
+	for _, item := range items {
+		b.WriteString(indent + "  - " + yamlQuote(item) + "\n")
+	}
+}
+
+// yamlQuote returns s as a double quoted scalar, json strings are valid yaml
+func yamlQuote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// yamlBlock returns multi line content as a literal block, anything a literal block
+// can not represent as is falls back to a quoted scalar
+func yamlBlock(s, indent string) string {
+	if !strings.Contains(strings.TrimSuffix(s, "\n"), "\n") || strings.ContainsAny(s, "\r\t") ||
+		strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") {
+		return yamlQuote(s)
+	}
+
+	header := "|"
+	if !strings.HasSuffix(s, "\n") {
+		header = "|-"
+	}
+
+	var b strings.Builder
+	b.WriteString(header)
+	for _, line := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+		b.WriteString("\n")
+		if line != "" {
+			b.WriteString(indent + line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package utl
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+)
+
+func TestCloudConfigRender(t *testing.T) {
+	cfg := CloudConfig{
+		Users: []CloudConfigUser{
+			{
+				Name:              "kolla",
+				Groups:            []string{"docker", "sudo"},
+				Shell:             "/bin/bash",
+				Sudo:              "ALL=(ALL) NOPASSWD:ALL",
+				SSHAuthorizedKeys: []string{"ssh-ed25519 AAAA kolla@deploy"},
+			},
+		},
+		Packages: []string{"docker.io"},
+		WriteFiles: []CloudConfigFile{
+			{Path: "/etc/motd", Content: "managed by terraform\nsecond line\n", Permissions: "0644"},
+			{Path: "/etc/hostname", Content: "controller01"},
+		},
+		RunCmd: []string{"systemctl enable --now docker"},
+	}
+
+	expected := `#cloud-config
+users:
+  - name: "kolla"
+    groups: "docker, sudo"
+    shell: "/bin/bash"
+    sudo: "ALL=(ALL) NOPASSWD:ALL"
+    ssh_authorized_keys:
+      - "ssh-ed25519 AAAA kolla@deploy"
+packages:
+  - "docker.io"
+write_files:
+  - path: "/etc/motd"
+    permissions: "0644"
+    content: |
+      managed by terraform
+      second line
+  - path: "/etc/hostname"
+    content: "controller01"
+runcmd:
+  - "systemctl enable --now docker"
+`
+	rendered, err := cfg.Render()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rendered != expected {
+		t.Errorf("unexpected document:\n%s", rendered)
+	}
+}
+
+func TestCloudConfigValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  CloudConfig
+	}{
+		{"empty user name", CloudConfig{Users: []CloudConfigUser{{Name: " "}}}},
+		{"duplicate user", CloudConfig{Users: []CloudConfigUser{{Name: "kolla"}, {Name: "kolla"}}}},
+		{"relative path", CloudConfig{WriteFiles: []CloudConfigFile{{Path: "etc/motd"}}}},
+		{"invalid permissions", CloudConfig{WriteFiles: []CloudConfigFile{{Path: "/etc/motd", Permissions: "rw-r--r--"}}}},
+		{"empty runcmd", CloudConfig{RunCmd: []string{""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.cfg.Render(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestRenderUserData(t *testing.T) {
+	cloudConfig := "#cloud-config\npackages:\n  - \"docker.io\"\n"
+	script := "#!/bin/bash\necho hello\n"
+
+	if rendered := RenderUserData(cloudConfig, ""); rendered != cloudConfig {
+		t.Errorf("expected cloud config as is, got %q", rendered)
+	}
+	if rendered := RenderUserData("", script); rendered != script {
+		t.Errorf("expected script as is, got %q", rendered)
+	}
+
+	rendered := RenderUserData(cloudConfig, script)
+	if rendered != RenderUserData(cloudConfig, script) {
+		t.Error("expected rendering to be stable")
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(rendered))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" {
+		t.Fatalf("unexpected content type %q: %v", mediaType, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for _, expected := range []struct{ contentType, body string }{
+		{"text/cloud-config", cloudConfig},
+		{"text/x-shellscript", script},
+	} {
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(part.Header.Get("Content-Type"), expected.contentType) {
+			t.Errorf("expected %s part, got %q", expected.contentType, part.Header.Get("Content-Type"))
+		}
+		if strings.TrimSpace(string(body)) != strings.TrimSpace(expected.body) {
+			t.Errorf("unexpected %s body %q", expected.contentType, body)
+		}
+	}
+	if _, err := reader.NextPart(); err != io.EOF {
+		t.Errorf("expected exactly two parts, got %v", err)
+	}
+}
+
+func TestCheckUserDataSize(t *testing.T) {
+	if err := CheckUserDataSize(strings.Repeat("a", 49000)); err != nil {
+		t.Error(err)
+	}
+	if err := CheckUserDataSize(strings.Repeat("a", 50000)); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -3,8 +3,10 @@ package utl
 import (
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"strings"
 )
 
 func TerraformListStringToSlice(tfList []types.String) []string {
@@ -29,6 +31,13 @@ func ResourceConfigureError(req *resource.ConfigureRequest, resp *resource.Confi
 	)
 }
 
+func EphemeralResourceConfigureError(req *ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	resp.Diagnostics.AddError(
+		"Unexpected ephemeral resource provider data",
+		fmt.Sprintf("Expected *api.Clinet, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+	)
+}
+
 func GetListDiffs(l1, l2 []types.String) []string {
 
 	m := make(map[string]bool)
@@ -98,3 +107,15 @@ func AssignStringIfChanged(old *types.String, new string) {
 		*old = nv
 	}
 }
+
+// TailLines returns the last n lines of s, a trailing newline does not count as a line
+func TailLines(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.TrimRight(s, "\n")
+	}
+	return strings.Join(lines[len(lines)-n:], "\n")
+}
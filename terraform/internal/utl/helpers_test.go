@@ -0,0 +1,21 @@
+package utl
+
+import "testing"
+
+func TestTailLines(t *testing.T) {
+	cases := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"a\nb\nc\n", 2, "b\nc"},
+		{"a\nb\nc", 5, "a\nb\nc"},
+		{"a\nb\nc", 0, ""},
+		{"", 3, ""},
+	}
+	for _, c := range cases {
+		if got := TailLines(c.in, c.n); got != c.want {
+			t.Errorf("TailLines(%q, %d) = %q, want %q", c.in, c.n, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package utl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// EncryptWithPGPKey encrypts plaintext for pgpKey and returns the binary message base64
+// encoded. pgpKey is either a base64 encoded public key or an ascii armored one
+func EncryptWithPGPKey(pgpKey, plaintext string) (string, error) {
+	entities, err := readPGPKey(pgpKey)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	w, err := openpgp.Encrypt(&buf, entities, nil, nil, nil)
+	if err != nil {
+		return "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+	if _, err := w.Write([]byte(plaintext)); err != nil {
+		return "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("error encrypting with pgp key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// ValidatePGPKey checks that pgpKey can be parsed into at least one public key
+func ValidatePGPKey(pgpKey string) error {
+	_, err := readPGPKey(pgpKey)
+	return err
+}
+
+func readPGPKey(pgpKey string) (openpgp.EntityList, error) {
+	pgpKey = strings.TrimSpace(pgpKey)
+	if strings.HasPrefix(pgpKey, "keybase:") {
+		return nil, fmt.Errorf("keybase keys are not supported, pass the public key itself")
+	}
+
+	var entities openpgp.EntityList
+	var err error
+	if strings.HasPrefix(pgpKey, "-----BEGIN") {
+		entities, err = openpgp.ReadArmoredKeyRing(strings.NewReader(pgpKey))
+	} else {
+		var raw []byte
+		raw, err = base64.StdEncoding.DecodeString(pgpKey)
+		if err != nil {
+			return nil, fmt.Errorf("pgp key is neither ascii armored nor base64 encoded: %w", err)
+		}
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(raw))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading pgp key: %w", err)
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("pgp key contains no public key")
+	}
+	return entities, nil
+}
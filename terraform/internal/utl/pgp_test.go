@@ -0,0 +1,74 @@
+package utl
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+func TestEncryptWithPGPKey(t *testing.T) {
+	entity, err := openpgp.NewEntity("test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var pub bytes.Buffer
+	if err := entity.Serialize(&pub); err != nil {
+		t.Fatal(err)
+	}
+
+	var armored bytes.Buffer
+	w, err := armor.Encode(&armored, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+
+	keys := map[string]string{
+		"base64":  base64.StdEncoding.EncodeToString(pub.Bytes()),
+		"armored": armored.String(),
+	}
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := EncryptWithPGPKey(key, "s3cret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(encrypted, "s3cret") {
+				t.Fatal("plaintext found in encrypted value")
+			}
+
+			raw, err := base64.StdEncoding.DecodeString(encrypted)
+			if err != nil {
+				t.Fatal(err)
+			}
+			md, err := openpgp.ReadMessage(bytes.NewReader(raw), openpgp.EntityList{entity}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain, err := io.ReadAll(md.UnverifiedBody)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(plain) != "s3cret" {
+				t.Fatalf("got %q", plain)
+			}
+		})
+	}
+}
+
+func TestValidatePGPKey(t *testing.T) {
+	for _, key := range []string{"", "keybase:someone", "not base64!", base64.StdEncoding.EncodeToString([]byte("garbage"))} {
+		if err := ValidatePGPKey(key); err == nil {
+			t.Errorf("expected error for %q", key)
+		}
+	}
+}
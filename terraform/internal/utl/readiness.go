@@ -0,0 +1,87 @@
+package utl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var readinessPollInterval = 5 * time.Second
+
+// WaitForTCP waits until a tcp connection to address can be established
+func WaitForTCP(ctx context.Context, address string, timeout time.Duration) error {
+	return pollUntilReady(ctx, timeout, func(ctx context.Context) error {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+}
+
+// WaitForHTTP waits until a GET request to url returns a 2xx status code
+func WaitForHTTP(ctx context.Context, url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: readinessPollInterval}
+	return pollUntilReady(ctx, timeout, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// WaitForConsoleMarker waits until the console log returned by getLog contains marker
+func WaitForConsoleMarker(ctx context.Context, getLog func(ctx context.Context) (string, error), marker string, timeout time.Duration) error {
+	return pollUntilReady(ctx, timeout, func(ctx context.Context) error {
+		log, err := getLog(ctx)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(log, marker) {
+			return fmt.Errorf("marker %q not found in console log", marker)
+		}
+		return nil
+	})
+}
+
+// pollUntilReady runs check until it succeeds, on timeout the last error of check is returned
+func pollUntilReady(ctx context.Context, timeout time.Duration, check func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	tick := time.NewTicker(readinessPollInterval)
+	defer tick.Stop()
+
+	for {
+		err := check(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("not ready after %s: %w", timeout, err)
+			}
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package utl
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func init() {
+	readinessPollInterval = 10 * time.Millisecond
+}
+
+func TestWaitForTCP(t *testing.T) {
+	ctx := context.Background()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+
+	if err := WaitForTCP(ctx, address, time.Second); err != nil {
+		t.Errorf("expected port to be reachable: %v", err)
+	}
+
+	listener.Close()
+	if err := WaitForTCP(ctx, address, 50*time.Millisecond); err == nil {
+		t.Error("expected closed port to time out")
+	}
+}
+
+func TestWaitForHTTP(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first requests fail like a service which is still starting
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := WaitForHTTP(ctx, server.URL, time.Second); err != nil {
+		t.Errorf("expected endpoint to become ready: %v", err)
+	}
+	if calls < 3 {
+		t.Errorf("expected at least 3 requests, got %d", calls)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	if err := WaitForHTTP(ctx, failing.URL, 50*time.Millisecond); err == nil {
+		t.Error("expected failing endpoint to time out")
+	}
+}
+
+func TestWaitForConsoleMarker(t *testing.T) {
+	ctx := context.Background()
+
+	logs := []string{"", "booting", "booting\ncloud-init finished\n"}
+	var calls int
+	getLog := func(ctx context.Context) (string, error) {
+		log := logs[calls]
+		if calls < len(logs)-1 {
+			calls++
+		}
+		return log, nil
+	}
+
+	if err := WaitForConsoleMarker(ctx, getLog, "cloud-init finished", time.Second); err != nil {
+		t.Errorf("expected marker to be found: %v", err)
+	}
+	if err := WaitForConsoleMarker(ctx, getLog, "never printed", 50*time.Millisecond); err == nil {
+		t.Error("expected missing marker to time out")
+	}
+}
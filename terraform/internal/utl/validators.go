@@ -2,8 +2,11 @@ package utl
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 )
@@ -58,4 +61,79 @@ func (v portValidator) Description(c context.Context) string {
 
 func (v portValidator) MarkdownDescription(context.Context) string {
 	return ""
-}
\ No newline at end of file
+}
+
+func NameTemplateValidator() nameTemplateValidator {
+	return nameTemplateValidator{}
+}
+
+type nameTemplateValidator struct {}
+
+func (v nameTemplateValidator) ValidateString(c context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	// a missing, extra or non integer verb is rendered as %!
+	if strings.Contains(fmt.Sprintf(req.ConfigValue.ValueString(), 1), "%!") {
+		resp.Diagnostics.AddError("Value must be a name template", "Name templates are required to contain exactly one integer verb, e.g. controller0%d")
+	}
+}
+
+func (v nameTemplateValidator) Description(c context.Context) string {
+	return ""
+}
+
+func (v nameTemplateValidator) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+
+func DurationValidator() durationValidator {
+	return durationValidator{}
+}
+
+type durationValidator struct {}
+
+func (v durationValidator) ValidateString(c context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	d, err := time.ParseDuration(req.ConfigValue.ValueString())
+	if err != nil || d <= 0 {
+		resp.Diagnostics.AddError("Value must be a positive duration", "Durations are required to be in Go duration format, e.g. 30s or 5m")
+	}
+}
+
+func (v durationValidator) Description(c context.Context) string {
+	return ""
+}
+
+func (v durationValidator) MarkdownDescription(context.Context) string {
+	return ""
+}
+
+func PGPKeyValidator() pgpKeyValidator {
+	return pgpKeyValidator{}
+}
+
+type pgpKeyValidator struct {}
+
+func (v pgpKeyValidator) ValidateString(c context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := ValidatePGPKey(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Value must be a pgp public key", err.Error())
+	}
+}
+
+func (v pgpKeyValidator) Description(c context.Context) string {
+	return ""
+}
+
+func (v pgpKeyValidator) MarkdownDescription(context.Context) string {
+	return ""
+}